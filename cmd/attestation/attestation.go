@@ -0,0 +1,34 @@
+// Package attestation manages the commands for the signed attestations of the images.
+package attestation
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+var opts struct {
+	presenter.Option
+
+	// key is the public key verifying the attestations
+	key string
+	// digest is the manifest digest the attestations must be about
+	digest string
+}
+
+// Cmd return the command related to the signed attestations.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "attestation",
+		Short: "Commands related to the signed attestations of the images",
+		Long: `Commands related to the signed attestations of the images.
+The attestations are in-toto statements in DSSE envelopes, written by
+the --attest flag of the image scan and image packages commands.`,
+	}
+
+	cmd.AddCommand(VerifyCmd())
+
+	cmd.PersistentFlags().StringVarP(
+		&opts.OutputFormat, "output", "o", "table", "output format of the result")
+
+	return cmd
+}
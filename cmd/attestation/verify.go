@@ -0,0 +1,67 @@
+package attestation
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/attest"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+// VerifyCmd will return the command for verifying the signatures of an attestation file.
+func VerifyCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify <file>",
+		Short: "Verify the signed attestations of a file",
+		Long: printtool.Tprintf(`Verify that every attestation of the file is signed by the key:
+    {{.appName}} attestation verify image.intoto.jsonl --key key.pub
+    {{.appName}} attestation verify image.intoto.jsonl --key key.pub --digest sha256:<manifest digest>
+The key is an ECDSA or ed25519 public key in PEM, or the private key signing the attestations.
+`, map[string]interface{}{
+			"appName": internal.ApplicationName,
+		}),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			go verifyAttestation(args[0])
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	verifyCmd.Flags().StringVar(&opts.key, "key", "", "the public key verifying the attestations")
+	verifyCmd.Flags().StringVar(
+		&opts.digest, "digest", "", "also check that the attestations are about the image of this manifest digest")
+
+	return verifyCmd
+}
+
+func verifyAttestation(path string) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		errMsg := "The attestation verification only supports table, json and markdown output"
+		e := cberr.NewError(cberr.ConfigErr, errMsg, nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	if opts.key == "" {
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ConfigErr, "The --key flag is required", nil)))
+		return
+	}
+
+	verifier, err := attest.LoadVerifier(opts.key)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	report, err := attest.VerifyFile(path, verifier, opts.digest)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	bus.Publish(bus.NewEvent(bus.AttestationVerified, presenter.NewPresenter(report, opts.Option), true))
+}
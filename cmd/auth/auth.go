@@ -3,17 +3,29 @@ package auth
 
 import (
 	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
 )
 
 // Cmd return the command related to credential.
 func Cmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "auth",
-		Short: "Set auth for cbctl",
-		Long:  `Set auth for cbctl`,
+		Short: "Manage auth for cbctl",
+		Long:  `Set, verify and migrate the auth for cbctl`,
+		// the passphrase of the encrypted credential file is only prompted by the auth commands,
+		// the other commands read it from the env
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			config.PassphrasePrompt = PromptPassphrase
+
+			if root := cmd.Root(); root.PersistentPreRun != nil {
+				root.PersistentPreRun(cmd, args)
+			}
+		},
 	}
 
 	cmd.AddCommand(SetCbAPIAccessCmd())
+	cmd.AddCommand(MigrateCmd())
+	cmd.AddCommand(StatusCmd())
 
 	return cmd
 }
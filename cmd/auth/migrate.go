@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+const (
+	storeAuto = "auto"
+
+	profileHeader = "Profile"
+	storeHeader   = "Store"
+	statusHeader  = "Status"
+	serviceHeader = "Service"
+	messageHeader = "Message"
+	hintHeader    = "Hint"
+)
+
+var migrateOpts struct {
+	store     string
+	presenter presenter.Option
+}
+
+// MigrateCmd will return the command moving the plaintext credentials into a credential store.
+func MigrateCmd() *cobra.Command {
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Move plaintext credentials into the keyring or an encrypted file",
+		Long: fmt.Sprintf(`Move the API IDs and Keys saved in plaintext in the config file into a credential store,
+and scrub them from the config file. The keyring is used if it is accessible, an encrypted file otherwise;
+the passphrase of the encrypted file is read from %s or prompted.`, config.PassphraseEnv),
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			go migrateCredentials()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	migrateCmd.Flags().StringVar(&migrateOpts.store, "store", storeAuto,
+		"the credential store to use: auto, keyring or file")
+	migrateCmd.Flags().StringVarP(&migrateOpts.presenter.OutputFormat, "output", "o", "table",
+		"output format of the result")
+
+	return migrateCmd
+}
+
+func migrateCredentials() {
+	store := migrateOpts.store
+
+	switch store {
+	case storeAuto:
+		store = ""
+	case config.StoreKeyring, config.StoreEncryptedFile:
+	default:
+		errMsg := fmt.Sprintf("Invalid credential store %q, must be one of auto, keyring or file", store)
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ConfigErr, errMsg, nil)))
+
+		return
+	}
+
+	if migrateOpts.presenter.OutputFormat == "cyclonedx" || migrateOpts.presenter.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The auth migrate only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	names := make([]string, 0)
+
+	for name, property := range config.Config().Properties {
+		if !property.StoresCredentials() && (property.CBApiID != "" || property.CBApiKey != "") {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		msg := fmt.Sprintf("No plaintext credentials found in %s", viper.ConfigFileUsed())
+		bus.Publish(bus.NewMessageEvent(msg, true))
+
+		return
+	}
+
+	sort.Strings(names)
+
+	result := &migration{}
+	failed := 0
+
+	for _, name := range names {
+		property := config.Config().Properties[name]
+		entry := migratedProfile{Profile: name, Status: "migrated"}
+
+		used, err := config.StoreCredentials(name, property.CBApiID, property.CBApiKey, store)
+		if err != nil {
+			entry.Status = "failed: " + cberr.ErrorMessage(err)
+			failed++
+		}
+
+		entry.Store = used
+		result.Profiles = append(result.Profiles, entry)
+	}
+
+	bus.Publish(bus.NewEvent(bus.PrintProfiles, presenter.NewPresenter(result, migrateOpts.presenter), failed == 0))
+
+	if failed > 0 {
+		errMsg := fmt.Sprintf("Failed to migrate the credentials of %d profiles, kept in plaintext", failed)
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ConfigErr, errMsg, nil)))
+	}
+}
+
+// migratedProfile is where the credentials of a profile have been moved.
+type migratedProfile struct {
+	Profile string `json:"profile"`
+	Store   string `json:"store,omitempty"`
+	Status  string `json:"status"`
+}
+
+// migration is the result of the credentials migration.
+type migration struct {
+	Profiles []migratedProfile `json:"profiles"`
+}
+
+// Title is the title of the migration.
+func (m *migration) Title() string {
+	return "Credentials migration:"
+}
+
+// Footer tells the plaintext credentials are scrubbed from the config file.
+func (m *migration) Footer() string {
+	return fmt.Sprintf("The migrated credentials are removed from %s\n", viper.ConfigFileUsed())
+}
+
+// Header is the header columns of the migration.
+func (m *migration) Header() []string {
+	return []string{profileHeader, storeHeader, statusHeader}
+}
+
+// Rows returns the migrated profiles as list of rows.
+func (m *migration) Rows() [][]string {
+	result := make([][]string, 0, len(m.Profiles))
+
+	for _, profile := range m.Profiles {
+		result = append(result, []string{profile.Profile, profile.Store, profile.Status})
+	}
+
+	return result
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"fmt"
+	"path/filepath"
 
 	"github.com/manifoldco/promptui"
 	"github.com/sirupsen/logrus"
@@ -12,7 +13,6 @@ import (
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
-	"github.com/zalando/go-keyring"
 )
 
 const numArgs = 2
@@ -48,16 +48,18 @@ func handleAuthAccessFromArgs(args []string) {
 	config.SetConfigByOption(user, config.CBApiID, apiID)
 	config.SetConfigByOption(user, config.CBApiKey, apiKey)
 
-	if errs := SaveCbAPIAccess(config.GetConfig(config.ActiveUserProfile), apiID, apiKey); errs != nil {
-		logrus.Errorf("Cannot save access in keyring: %v", errs)
+	store, err := SaveCbAPIAccess(config.GetConfig(config.ActiveUserProfile), apiID, apiKey)
+	if err != nil {
+		logrus.Errorf("Cannot save access in keyring or encrypted file: %v", err)
 
-		msg = fmt.Sprintf("No keyring found; storing credentials in %s instead", viper.ConfigFileUsed())
+		msg = fmt.Sprintf("No keyring or encrypted file passphrase found; storing credentials in %s instead",
+			viper.ConfigFileUsed())
 		bus.Publish(bus.NewMessageEvent(msg, true))
 
 		return
 	}
 
-	msg = "Saving the Carbon Black API Access in keyring"
+	msg = fmt.Sprintf("Saving the Carbon Black API Access in %s", storeDescription(store))
 	bus.Publish(bus.NewMessageEvent(msg, true))
 }
 
@@ -66,19 +68,19 @@ func handleAuthAccessInInteractiveMode() {
 
 	user := config.Config().ActiveUserProfile
 
+	label := "No keyring detected, do you want to save access into an encrypted file"
 	if config.Config().AccessToKeyring {
-		prompt := promptui.Prompt{
-			Label:     "Valid keyring detected, do you want to save access into keyring",
-			IsConfirm: true,
-		}
+		label = "Valid keyring detected, do you want to save access into keyring"
+	}
 
-		if input, _ := prompt.Run(); input == "y" {
-			config.Config().Properties[user].AuthByKeyring = true
-		} else {
-			config.Config().Properties[user].AuthByKeyring = false
-		}
+	prompt := promptui.Prompt{
+		Label:     label,
+		IsConfirm: true,
 	}
 
+	input, _ := prompt.Run()
+	useStore := input == "y"
+
 	authOptions := []config.Option{config.CBApiID, config.CBApiKey}
 	for _, option := range authOptions {
 		label := fmt.Sprintf("%v:", option)
@@ -101,23 +103,53 @@ func handleAuthAccessInInteractiveMode() {
 		config.SetConfigByOption(user, option, input)
 	}
 
-	if config.Config().Properties[user].AuthByKeyring {
-		_ = SaveCbAPIAccess(user, config.GetConfig(config.CBApiID), config.GetConfig(config.CBApiKey))
+	// the credentials are kept in the config file if they are not saved in a credential store
+	saved := false
+
+	if useStore {
+		property := config.Config().Properties[user]
+		_, err := SaveCbAPIAccess(user, property.CBApiID, property.CBApiKey)
+		if err != nil {
+			logrus.Errorf("Cannot save access in keyring or encrypted file: %v", err)
+		}
+
+		saved = err == nil
+	}
+
+	if !saved {
+		config.UnstoreCredentials(user)
 	}
 
 	msg = "Saving the Carbon Black API Access"
 	bus.Publish(bus.NewMessageEvent(msg, true))
 }
 
-// SaveCbAPIAccess will save the cb access to credential store via keyring.
-func SaveCbAPIAccess(profile, apiID, apiKey string) []error {
-	errKey := keyring.Set(profile, config.CBApiKey.String(), apiKey)
-	errID := keyring.Set(profile, config.CBApiID.String(), apiID)
+// SaveCbAPIAccess will save the cb access to credential store via keyring,
+// or in the encrypted credential file if no keyring is accessible; the store used is returned.
+func SaveCbAPIAccess(profile, apiID, apiKey string) (string, error) {
+	return config.StoreCredentials(profile, apiID, apiKey, "")
+}
+
+// PromptPassphrase will ask the passphrase of the encrypted credential file, or ask it again to confirm it.
+func PromptPassphrase(confirm bool) (string, error) {
+	label := fmt.Sprintf("Passphrase of the encrypted credential file (or set %s)", config.PassphraseEnv)
+	if confirm {
+		label = "Confirm the passphrase of the new encrypted credential file"
+	}
+
+	prompt := promptui.Prompt{
+		Label:     label,
+		Templates: printtool.Templates(),
+		Mask:      '#',
+	}
+
+	return prompt.Run()
+}
 
-	if errID == nil && errKey == nil {
-		config.Config().Properties[profile].AuthByKeyring = true
-		return nil
+func storeDescription(store string) string {
+	if store == config.StoreEncryptedFile {
+		return filepath.Join(config.Config().ConfigHome, config.CredentialFileName)
 	}
 
-	return []error{errKey, errID}
+	return store
 }
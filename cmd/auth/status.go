@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/doctor"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+var statusOpts struct {
+	// require are the services the api key must access
+	require   []string
+	presenter presenter.Option
+}
+
+// StatusCmd will return the command verifying the credentials of the active profile.
+func StatusCmd() *cobra.Command {
+	statusCmd := &cobra.Command{
+		Use:     "status",
+		Aliases: []string{"whoami"},
+		Short:   "Verify the credentials of the active profile",
+		Long: `Verify the credentials of the active profile with an authenticated request to each service,
+and show the services the api key can access, the org key, the SaaS url and where the credentials come from.
+Exits non-zero if the api key can not access a required service.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				handleStatus(ctx)
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	statusCmd.Flags().StringSliceVar(&statusOpts.require, "require",
+		[]string{doctor.ServiceAnalyzer, doctor.ServiceGuardrails, doctor.ServiceManagement},
+		"the services the api key must access")
+	statusCmd.Flags().StringVarP(&statusOpts.presenter.OutputFormat, "output", "o", "table",
+		"output format of the result")
+
+	return statusCmd
+}
+
+func handleStatus(ctx context.Context) {
+	if statusOpts.presenter.OutputFormat == "cyclonedx" || statusOpts.presenter.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The auth status only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	status := &authStatus{
+		Profile:          config.GetConfig(config.ActiveUserProfile),
+		SaasURL:          config.GetConfig(config.SaasURL),
+		OrgKey:           config.GetConfig(config.OrgKey),
+		APIID:            config.GetConfig(config.CBApiID),
+		CredentialSource: config.CredentialSource(),
+	}
+
+	apiKey := config.GetConfig(config.CBApiKey)
+
+	if err := config.CredentialError(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+
+		return
+	}
+
+	if status.SaasURL == "" || status.OrgKey == "" || status.APIID == "" || apiKey == "" {
+		msg := fmt.Sprintf("The profile %s misses the saas_url, org_key, api id or api key", status.Profile)
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.HTTPNotAllowedErr, msg, nil)))
+
+		return
+	}
+
+	services := doctor.Services(status.SaasURL, status.OrgKey)
+	known := make(map[string]bool)
+
+	for _, service := range services {
+		known[service.Name] = true
+	}
+
+	required := make(map[string]bool)
+
+	for _, name := range statusOpts.require {
+		if !known[name] {
+			errMsg := fmt.Sprintf("Invalid required service %q, must be analyzer, guardrails or management", name)
+			bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ConfigErr, errMsg, nil)))
+
+			return
+		}
+
+		required[name] = true
+	}
+
+	session := httptool.NewRequestSession(status.APIID, apiKey)
+
+	missing := make([]string, 0)
+
+	for _, service := range services {
+		result := doctor.CheckAccess(ctx, session, service)
+		status.Add(result)
+
+		if required[service.Name] && result.Status != doctor.StatusPass {
+			missing = append(missing, service.Name)
+		}
+	}
+
+	if ctx.Err() != nil {
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.InterruptedErr, "Auth status interrupted", ctx.Err())))
+		return
+	}
+
+	bus.Publish(bus.NewEvent(bus.PrintProfiles, presenter.NewPresenter(status, statusOpts.presenter), len(missing) == 0))
+
+	if len(missing) > 0 {
+		msg := fmt.Sprintf("The api key can not access the required services: %s", strings.Join(missing, ", "))
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.HTTPNotAllowedErr, msg, nil)))
+	}
+}
+
+// authStatus is the identity of the active profile with the access of its api key to each service.
+type authStatus struct {
+	Profile          string `json:"profile"`
+	SaasURL          string `json:"saas_url"`
+	OrgKey           string `json:"org_key"`
+	APIID            string `json:"api_id"`
+	CredentialSource string `json:"credential_source"`
+	doctor.Report
+}
+
+// Title shows the identity of the active profile, it is only authenticated once a service granted the access.
+func (a *authStatus) Title() string {
+	state := "Not authenticated as"
+
+	for _, result := range a.Results {
+		if result.Message == doctor.AccessGranted {
+			state = "Authenticated as"
+			break
+		}
+	}
+
+	return fmt.Sprintf("%s %s in org %s on %s (profile %s, credentials from %s):",
+		state, a.APIID, a.OrgKey, a.SaasURL, a.Profile, a.CredentialSource)
+}
+
+// Footer summarizes the services the api key can access.
+func (a *authStatus) Footer() string {
+	return fmt.Sprintf("%d of %d services accessible\n", a.Count(doctor.StatusPass), len(a.Results))
+}
+
+// Header is the header columns of the access to each service.
+func (a *authStatus) Header() []string {
+	return []string{serviceHeader, statusHeader, messageHeader, hintHeader}
+}
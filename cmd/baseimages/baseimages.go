@@ -0,0 +1,39 @@
+// Package baseimages manages the commands for the catalog of known base images.
+package baseimages
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/baseimage"
+)
+
+var catalogFile string
+
+// Cmd return the command related to the base image catalog.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "base-images",
+		Short: "Manage the catalog of known base images",
+		Long: `Manage the catalog of known base images.
+The catalog is used by image scan to split vulnerabilities into the ones
+inherited from the base image and the ones introduced by the image.`,
+	}
+
+	cmd.AddCommand(LearnCmd())
+	cmd.AddCommand(ListCmd())
+
+	cmd.PersistentFlags().StringVar(
+		&catalogFile, "base-images", "",
+		"the catalog of known base images (default is base_images.yaml under the config home)")
+
+	return cmd
+}
+
+func loadCatalog() (*baseimage.Catalog, error) {
+	path := catalogFile
+	if path == "" {
+		path = baseimage.DefaultCatalogPath(config.Config().ConfigHome)
+	}
+
+	return baseimage.LoadCatalog(path)
+}
@@ -0,0 +1,109 @@
+package baseimages
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/scan"
+)
+
+var (
+	name       string
+	scanOption scan.Option
+)
+
+// LearnCmd will return the command for adding a base image to the catalog.
+func LearnCmd() *cobra.Command {
+	learnCmd := &cobra.Command{
+		Use:   "learn <image>",
+		Short: "Add a base image to the catalog",
+		Long: printtool.Tprintf(`Read the layers of an image and save it as a known base image:
+    {{.appName}} base-images learn alpine:3.13
+    {{.appName}} base-images learn path/to/base.tar --name mycompany/base:1.0
+`, map[string]interface{}{
+			"appName": internal.ApplicationName,
+		}),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				learnBaseImage(ctx, args[0])
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	learnCmd.Flags().StringVar(
+		&name, "name", "", "the name of the base image in the catalog (default is the input)")
+	learnCmd.Flags().StringVar(
+		&scanOption.Credential, "cred", "", "use `USERNAME[:PASSWORD]` for accessing the registry")
+	learnCmd.Flags().BoolVar(
+		&scanOption.BypassDockerDaemon, "bypass-docker-daemon", false,
+		"try to pull image without docker daemon")
+
+	return learnCmd
+}
+
+func learnBaseImage(ctx context.Context, input string) {
+	catalog, err := loadCatalog()
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	registryHandler := scan.NewRegistryHandler()
+
+	img, err := registryHandler.LoadImage(ctx, input, scanOption)
+	if ctx.Err() != nil {
+		scan.Cleanup()
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.InterruptedErr, "Image pull interrupted", ctx.Err())))
+
+		return
+	}
+
+	if err != nil {
+		msg := fmt.Sprintf("Failed to pull image for input %s", input)
+		e := cberr.NewError(cberr.ImageLoadErr, msg, err)
+		bus.Publish(bus.NewErrorEvent(e))
+		logrus.Errorln(e)
+
+		return
+	}
+
+	defer func() {
+		if err := img.Cleanup(); err != nil {
+			logrus.WithError(err).Errorf("failed to clean up files for image [%s]", input)
+		}
+
+		scan.Cleanup()
+	}()
+
+	layerDigests := make([]string, 0, len(img.Layers))
+	for _, layer := range img.Layers {
+		layerDigests = append(layerDigests, layer.Metadata.Digest)
+	}
+
+	baseImageName := name
+	if baseImageName == "" {
+		baseImageName = input
+	}
+
+	catalog.Learn(baseImageName, layerDigests)
+
+	if err := catalog.Save(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	msg := fmt.Sprintf("Base image %s with %d layers saved in %s", baseImageName, len(layerDigests), catalog.Path())
+	bus.Publish(bus.NewMessageEvent(msg, true))
+}
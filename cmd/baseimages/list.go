@@ -0,0 +1,46 @@
+package baseimages
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+)
+
+// ListCmd will return the command for listing the base images in the catalog.
+func ListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the base images in the catalog",
+		Long:  `List the base images in the catalog.`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			go listBaseImages()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+}
+
+func listBaseImages() {
+	catalog, err := loadCatalog()
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	if catalog.IsEmpty() {
+		msg := fmt.Sprintf("No base image found in %s", catalog.Path())
+		bus.Publish(bus.NewMessageEvent(msg, true))
+
+		return
+	}
+
+	lines := make([]string, 0, len(catalog.BaseImages))
+	for _, baseImageName := range catalog.Names() {
+		lines = append(lines, fmt.Sprintf("%s (%d layers)", baseImageName, len(catalog.BaseImages[baseImageName])))
+	}
+
+	bus.Publish(bus.NewMessageEvent(strings.Join(lines, "\n"), true))
+}
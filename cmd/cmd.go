@@ -5,36 +5,54 @@ import (
 	"github.com/anchore/stereoscope"
 	"github.com/anchore/syft/syft"
 	partybus "github.com/wagoodman/go-partybus"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/attestation"
 	"github.com/vmware/carbon-black-cloud-container-cli/cmd/auth"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/baseimages"
 	configcmd "github.com/vmware/carbon-black-cloud-container-cli/cmd/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/doctor"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/fs"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/history"
 	"github.com/vmware/carbon-black-cloud-container-cli/cmd/image"
 	"github.com/vmware/carbon-black-cloud-container-cli/cmd/k8sobject"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/sbom"
 	"github.com/vmware/carbon-black-cloud-container-cli/cmd/user"
 	"github.com/vmware/carbon-black-cloud-container-cli/cmd/version"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/logtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 )
 
 var defaultConfigHome string
 
 const (
-	permModeReadWrite        = 0666
 	permModeReadWriteExecute = 0700
+
+	bytesPerMegabyte     = 1 << 20
+	defaultLogMaxSize    = 10
+	defaultLogMaxBackups = 3
 )
 
 func init() {
 	setGlobalCliOptions()
 	addSubCommands()
 
+	// the commands failing exit from the ui, the metrics are written before
+	logrus.RegisterExitHandler(writeMetrics)
+
 	cobra.OnInitialize(
 		initEventChan,
 		initLog,
@@ -55,7 +73,13 @@ func addSubCommands() {
 	rootCmd.AddCommand(auth.Cmd())
 	rootCmd.AddCommand(user.Cmd())
 	rootCmd.AddCommand(image.Cmd())
+	rootCmd.AddCommand(fs.Cmd())
+	rootCmd.AddCommand(sbom.Cmd())
+	rootCmd.AddCommand(attestation.Cmd())
 	rootCmd.AddCommand(k8sobject.Cmd())
+	rootCmd.AddCommand(baseimages.Cmd())
+	rootCmd.AddCommand(history.Cmd())
+	rootCmd.AddCommand(doctor.Cmd())
 }
 
 // initLog will initialize the debug log, if set by user.
@@ -66,9 +90,8 @@ func initLog() {
 		return
 	}
 
-	if file, err := os.OpenFile(flag.Value.String(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, permModeReadWrite); err == nil {
-		logrus.SetLevel(logrus.DebugLevel)
-		logrus.SetOutput(file)
+	if file, err := openLogFile(flag.Value.String()); err == nil {
+		setupLog(file)
 
 		msg := fmt.Sprintf("[You are in DEBUG mode, the debug log will be saved in %s]", flag.Value.String())
 		bus.Publish(bus.NewMessageEvent(msg, false))
@@ -80,9 +103,8 @@ func initLog() {
 	// create folder in the default config home if not exists
 	createFolder(defaultConfigHome)
 
-	if file, err := os.OpenFile(flag.NoOptDefVal, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, permModeReadWrite); err == nil {
-		logrus.SetLevel(logrus.DebugLevel)
-		logrus.SetOutput(file)
+	if file, err := openLogFile(flag.NoOptDefVal); err == nil {
+		setupLog(file)
 
 		msg := fmt.Sprintf(
 			"[You are in DEBUG mode, cannot save log in %s, the debug log will be saved in %s]",
@@ -91,6 +113,27 @@ func initLog() {
 	}
 }
 
+// openLogFile will open the debug log, rotated by its max size.
+func openLogFile(path string) (*logtool.RotatingFile, error) {
+	maxSize, _ := rootCmd.PersistentFlags().GetInt("log-max-size")
+	maxBackups, _ := rootCmd.PersistentFlags().GetInt("log-max-backups")
+
+	return logtool.OpenRotatingFile(path, int64(maxSize)*bytesPerMegabyte, maxBackups)
+}
+
+// setupLog will send the debug logs to the file in the format set by user, with the secrets redacted.
+func setupLog(file io.Writer) {
+	logrus.SetLevel(logrus.DebugLevel)
+
+	format := rootCmd.Flag("log-format").Value.String()
+	if err := logtool.Setup(file, format); err != nil {
+		_ = logtool.Setup(file, logtool.FormatText)
+
+		msg := fmt.Sprintf("Invalid --log-format, using text: %v", err)
+		bus.Publish(bus.NewMessageEvent(msg, false))
+	}
+}
+
 // initEventChan will initialize the event channel and set it to bus singleton.
 func initEventChan() {
 	bufferSize := 10
@@ -124,7 +167,45 @@ func initConfig() {
 		createFolder(defaultConfigHome)
 	}
 
-	config.LoadAppConfig()
+	config.Config().ConfigHome = defaultConfigHome
+	if err := config.LoadAppConfig(); err != nil {
+		// keep going with what could be loaded, so that the config can still be fixed with the cli
+		msg := fmt.Sprintf("Problems found in config file %s: %s", viper.ConfigFileUsed(), cberr.ErrorMessage(err))
+		bus.Publish(bus.NewMessageEvent(msg, false))
+		logrus.Errorln(err)
+	}
+
+	switch ui := config.Config().CliOpt.UI; ui {
+	case terminalui.UIAuto, terminalui.UIPlain, terminalui.UIDynamic, terminalui.UIJSON:
+	default:
+		config.Config().CliOpt.UI = terminalui.UIAuto
+		msg := fmt.Sprintf("Invalid --ui %q, using auto: only auto, plain, dynamic and json are supported", ui)
+		bus.Publish(bus.NewMessageEvent(msg, false))
+	}
+
+	if err := logtool.AddPatterns(strings.Fields(config.GetConfig(config.LogRedactPatterns))...); err != nil {
+		msg := fmt.Sprintf("Invalid log_redact_patterns, the default patterns are used: %v", err)
+		bus.Publish(bus.NewMessageEvent(msg, false))
+	}
+
+	httptool.SetDefaultRetryPolicy(httptool.ParseRetryPolicy(
+		config.GetConfig(config.RetryMaxAttempts), config.GetConfig(config.RetryMaxDelay)))
+
+	transportOptions := httptool.TransportOptions{
+		ProxyURL:   config.GetConfig(config.ProxyURL),
+		NoProxy:    config.GetConfig(config.NoProxy),
+		CABundle:   config.GetConfig(config.CABundle),
+		ClientCert: config.GetConfig(config.ClientCert),
+		ClientKey:  config.GetConfig(config.ClientKey),
+	}
+
+	if err := httptool.SetDefaultTransportOptions(transportOptions); err != nil {
+		// keep going with the default transport, so that "cbctl doctor" can still report the invalid settings
+		msg := fmt.Sprintf("Invalid transport settings, run \"cbctl doctor\" for details: %s", cberr.ErrorMessage(err))
+		bus.Publish(bus.NewMessageEvent(msg, false))
+		logrus.Errorln(err)
+	}
+
 	logrus.Debug("Configuration loaded")
 }
 
@@ -145,11 +226,39 @@ func setGlobalCliOptions() {
 	rootCmd.PersistentFlags().Bool(flag, false, "display ui on plain mode")
 	_ = viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag))
 
+	flag = "ui"
+	rootCmd.PersistentFlags().String(flag, terminalui.UIAuto, "display ui: auto, plain, dynamic or json (one event per line)")
+	_ = viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag))
+
 	flag = "debug"
 	logDefaultName := fmt.Sprintf("%s/debug.log", defaultConfigHome)
 	rootCmd.PersistentFlags().String(flag, logDefaultName, "enable debug log")
 	rootCmd.Flag(flag).NoOptDefVal = logDefaultName
 
+	rootCmd.PersistentFlags().String("log-format", logtool.FormatText, "format of the debug log: text or json")
+	rootCmd.PersistentFlags().Int("log-max-size", defaultLogMaxSize,
+		"max size in MB of the debug log before it is rotated, 0 to disable the rotation")
+	rootCmd.PersistentFlags().Int("log-max-backups", defaultLogMaxBackups, "max count of rotated debug logs kept")
+
+	flag = "metrics-file"
+	rootCmd.PersistentFlags().String(flag, "",
+		"write the duration of each stage and the resource usage of the command to the file")
+	_ = viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag))
+
+	flag = "metrics-format"
+	rootCmd.PersistentFlags().String(flag, metrictool.FormatJSON, "format of the metrics file: json or openmetrics")
+	_ = viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag))
+
+	flag = "otlp-endpoint"
+	rootCmd.PersistentFlags().String(flag, "", fmt.Sprintf(
+		"export the stages of the command as OpenTelemetry spans to the OTLP/HTTP endpoint (env: %s)",
+		metrictool.OTLPEndpointEnv))
+	_ = viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag))
+
+	flag = "cb-api-key-stdin"
+	rootCmd.PersistentFlags().Bool(flag, false, "read the API Key to be used for authorization from stdin")
+	_ = viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag))
+
 	for flag, usage := range config.ConfigFileOverrides {
 		rootCmd.PersistentFlags().String(flag, "", usage)
 		_ = viper.BindPFlag(flag, rootCmd.PersistentFlags().Lookup(flag))
@@ -20,20 +20,47 @@ const numArgs = 2
 
 // Cmd will return the config command.
 func Cmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "config <option> <value>",
 		Short: "Manage Carbon Black configuration",
 		Long: printtool.Tprintf(`Get or set an octarine config option.
 To get an option use '{{.appName}} config <option>'
 To set an option use '{{.appName}} config <option> <value>'
 To set configs in interactive mode use '{{.appName}} config'
+To share user profiles use '{{.appName}} config export' and '{{.appName}} config import <file>'
+To show the config use '{{.appName}} config show', add --effective --sources to see where each value comes from
+
+A project config file {{.projectConfig}} found from the working directory up to the root overrides the build and
+scan options of the active profile: default_build_step, namespace, fail_on, baseline_file, catalog_scope,
+catalogers, catalog_exclusions and search_archives. The env variables and flags override both.
 
 Available options:
-  active_user_profile - Current user profile
-  org_key             - Org key
-  saas_url            - Cloud SaaS url
+  active_user_profile  - Current user profile
+  org_key              - Org key
+  saas_url             - Cloud SaaS url
+  history_max_entries  - Max count of scan results kept in the local history (default 100)
+  history_max_age_days - Max age in days of scan results kept in the local history (default 90)
+  retry_max_attempts   - Max count of attempts for a request to the backend (default 4)
+  retry_max_delay      - Max delay in seconds between two attempts of a request (default 30)
+  proxy_url            - Proxy for the connections to the backend (default from HTTPS_PROXY)
+  no_proxy             - Comma-separated hosts connected without the proxy (default from NO_PROXY)
+  ca_bundle            - PEM file of the extra CA certificates trusted for the backend
+  client_cert          - PEM file of the client certificate for mutual TLS
+  client_key           - PEM file of the client key for mutual TLS
+  namespace            - Default namespace to validate the images and resources
+  fail_on              - Default severity from which the scan exits with a policy violation
+  baseline_file        - Default baseline file of the scan
+  cb_api_key_file      - File holding the api key, used instead of the saved one
+  credential_process   - Command printing the api id and key as json {"api_id", "api_key", "expires_at"},
+                         the credentials are cached until expires_at
+  log_redact_patterns  - Space-separated regular expressions of the secrets redacted from the debug log
+  catalog_scope        - Default layers of the image cataloged: squashed or all-layers (default squashed)
+  catalogers           - Default space-separated catalogers enabled, or disabled if prefixed with "-"
+  catalog_exclusions   - Default space-separated globs of the paths not cataloged, e.g. **/test/**
+  search_archives      - Default archives searched for packages: indexed, all or none (default indexed)
 `, map[string]interface{}{
-			"appName": internal.ApplicationName,
+			"appName":       internal.ApplicationName,
+			"projectConfig": config.ProjectConfigName,
 		}),
 		Args: cobra.MaximumNArgs(numArgs),
 		Run: func(cmd *cobra.Command, args []string) {
@@ -49,6 +76,12 @@ Available options:
 			terminalui.NewDisplay().DisplayEvents()
 		},
 	}
+
+	cmd.AddCommand(ExportCmd())
+	cmd.AddCommand(ImportCmd())
+	cmd.AddCommand(ShowCmd())
+
+	return cmd
 }
 
 func handleConfigFromArgs(args []string) {
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"io"
+	"io/ioutil"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"sigs.k8s.io/yaml"
+)
+
+const exportFileMode = 0600
+
+var exportOpts struct {
+	profiles       []string
+	includeSecrets bool
+	file           string
+}
+
+// ExportCmd will return the config export sub command.
+func ExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export user profiles",
+		Long: `Export user profiles in the config file format, to be imported with 'config import'.
+The api id and key are only exported with --include-secrets, including those stored in the keyring.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			go exportProfiles()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	exportCmd.Flags().StringSliceVar(&exportOpts.profiles, "profile", nil, "the profiles to export (default all)")
+	exportCmd.Flags().BoolVar(&exportOpts.includeSecrets, "include-secrets", false, "also export the api id and key")
+	exportCmd.Flags().StringVarP(&exportOpts.file, "file", "f", "", "write to this file instead of the stdout")
+
+	return exportCmd
+}
+
+func exportProfiles() {
+	names := make([]string, 0, len(exportOpts.profiles))
+	for _, p := range exportOpts.profiles {
+		names = append(names, config.ConvertToValidProfileName(p))
+	}
+
+	settings, err := config.ExportProfiles(names, exportOpts.includeSecrets)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	content, err := yaml.Marshal(settings)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ConfigErr, "Failed to export profiles", err)))
+		return
+	}
+
+	if exportOpts.file != "" {
+		if err := ioutil.WriteFile(exportOpts.file, content, exportFileMode); err != nil {
+			bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ConfigErr, "Failed to write exported profiles", err)))
+			return
+		}
+
+		bus.Publish(bus.NewMessageEvent("Profiles exported to "+exportOpts.file, true))
+
+		return
+	}
+
+	bus.Publish(bus.NewEvent(bus.PrintProfiles, exportedProfiles(content), true))
+}
+
+// exportedProfiles presents the exported profiles as they are.
+type exportedProfiles []byte
+
+// Present will write the exported profiles to the output.
+func (e exportedProfiles) Present(output io.Writer) error {
+	_, err := output.Write(e)
+	return err
+}
+
+// Title is the title of the exported profiles.
+func (e exportedProfiles) Title() string {
+	return "# exported profiles"
+}
+
+// Footer is empty for the exported profiles.
+func (e exportedProfiles) Footer() string {
+	return ""
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+)
+
+// ImportCmd will return the config import sub command.
+func ImportCmd() *cobra.Command {
+	var overwrite bool
+
+	importCmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import user profiles",
+		Long: `Import the user profiles of a file in the config file format, e.g. exported with 'config export'.
+The file is validated before any profile is imported, the api id and key are saved in the keyring if it is accessible.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			go importProfiles(args[0], overwrite)
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	importCmd.Flags().BoolVar(&overwrite, "overwrite", false, "replace the existing profiles with the imported ones")
+
+	return importCmd
+}
+
+func importProfiles(file string, overwrite bool) {
+	settings, err := config.ReadConfigFile(file)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	names, err := config.ImportProfiles(settings, overwrite)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	msg := fmt.Sprintf("Imported %d profiles: %s", len(names), strings.Join(names, ", "))
+	bus.Publish(bus.NewMessageEvent(msg, true))
+}
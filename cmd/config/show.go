@@ -0,0 +1,121 @@
+package config
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+const (
+	optionHeader = "Option"
+	valueHeader  = "Value"
+	sourceHeader = "Source"
+)
+
+var showOpts struct {
+	effective bool
+	sources   bool
+	presenter presenter.Option
+}
+
+// ShowCmd will return the config show sub command.
+func ShowCmd() *cobra.Command {
+	showCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the config of the active user profile",
+		Long: `Show the config of the active user profile, with the api key masked.
+With --effective, the values of the project config file, env variables and flags are applied,
+with --sources, where each value comes from is also shown.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			go showConfig()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	showCmd.Flags().BoolVar(&showOpts.effective, "effective", false,
+		"apply the project config file, env variables and flags")
+	showCmd.Flags().BoolVar(&showOpts.sources, "sources", false, "show where each value comes from")
+	showCmd.Flags().StringVarP(&showOpts.presenter.OutputFormat, "output", "o", "table", "output format of the result")
+
+	return showCmd
+}
+
+func showConfig() {
+	if showOpts.presenter.OutputFormat == "cyclonedx" || showOpts.presenter.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The config show only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	options := config.SavedConfig()
+	if showOpts.effective {
+		options = config.EffectiveConfig()
+	}
+
+	result := &configOptions{
+		Profile:     config.GetConfig(config.ActiveUserProfile),
+		ProjectFile: config.Config().ProjectConfigFile,
+		Options:     options,
+		sources:     showOpts.sources,
+	}
+
+	bus.Publish(bus.NewEvent(bus.PrintProfiles, presenter.NewPresenter(result, showOpts.presenter), true))
+}
+
+// configOptions is the config of the active user profile to present.
+type configOptions struct {
+	Profile     string                   `json:"profile"`
+	ProjectFile string                   `json:"project_file,omitempty"`
+	Options     []config.EffectiveOption `json:"options"`
+	sources     bool
+}
+
+// Title is the title of the config.
+func (c *configOptions) Title() string {
+	return "Config of user profile " + c.Profile + ":"
+}
+
+// Footer shows the project config file, if any.
+func (c *configOptions) Footer() string {
+	if c.ProjectFile == "" {
+		return ""
+	}
+
+	return "Project config file: " + c.ProjectFile + "\n"
+}
+
+// Header is the header columns of the config.
+func (c *configOptions) Header() []string {
+	if c.sources {
+		return []string{optionHeader, valueHeader, sourceHeader}
+	}
+
+	return []string{optionHeader, valueHeader}
+}
+
+// Rows returns the options of the config as list of rows.
+func (c *configOptions) Rows() [][]string {
+	result := make([][]string, 0, len(c.Options))
+
+	for _, option := range c.Options {
+		row := []string{option.Option, option.Value}
+
+		if c.sources {
+			source := string(option.Source)
+			if option.Origin != "" {
+				source += " (" + option.Origin + ")"
+			}
+
+			row = append(row, source)
+		}
+
+		result = append(result, row)
+	}
+
+	return result
+}
@@ -0,0 +1,119 @@
+// Package doctor manages the command for diagnosing the environment of the cli tool.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/doctor"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+var opts struct {
+	presenter.Option
+
+	// image is the image to check the registry auth for
+	image string
+}
+
+// Cmd will return the doctor command.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Diagnose the environment of the cli tool",
+		Long: `Diagnose the environment of the cli tool: the config and the active profile,
+the keyring, the api credentials, the reachability of the analyzer and guardrails services,
+the docker or podman daemon, the registry auth of an image, the temp dir and the version.
+Every check reports pass, warn or fail, with a hint to fix the failed ones.`,
+		Args: cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				runDiagnostics(ctx)
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "output format of the result")
+	cmd.Flags().StringVar(&opts.image, "image", "", "also check the registry auth for this image")
+
+	return cmd
+}
+
+func runDiagnostics(ctx context.Context) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The doctor command only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	profile := config.GetConfig(config.ActiveUserProfile)
+	saasURL := config.GetConfig(config.SaasURL)
+	orgKey := config.GetConfig(config.OrgKey)
+	apiID := config.GetConfig(config.CBApiID)
+	apiKey := config.GetConfig(config.CBApiKey)
+
+	if err := config.CredentialError(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+
+		return
+	}
+
+	// a single attempt per request, so that the latency is not hidden by the retries
+	session := httptool.NewRequestSession(apiID, apiKey)
+	session.SetRetryPolicy(httptool.RetryPolicy{MaxAttempts: 1})
+
+	report := &doctor.Report{}
+	report.Add(doctor.CheckConfig(viper.ConfigFileUsed()))
+	report.Add(doctor.CheckProfile(profile, saasURL, orgKey))
+	report.Add(doctor.CheckKeyring(profile))
+	report.Add(doctor.CheckTransport(httptool.DefaultTransportOptions(), saasURL))
+
+	if saasURL != "" && orgKey != "" {
+		// the credentials are checked on the management service, the others are only checked to be reachable
+		for _, service := range doctor.Services(saasURL, orgKey) {
+			if service.Name == doctor.ServiceManagement {
+				report.Add(doctor.CheckCredentials(ctx, session, apiID, apiKey, service.URL))
+			} else {
+				report.Add(doctor.CheckEndpoint(ctx, session, service.Name, service.URL))
+			}
+		}
+	}
+
+	report.Add(doctor.CheckDaemonSocket(doctor.DaemonHosts()))
+
+	if opts.image != "" {
+		report.Add(doctor.CheckRegistryAuth(ctx, opts.image))
+	}
+
+	report.Add(doctor.CheckTempDir(os.TempDir(), doctor.MinTempDirSpace))
+
+	updateAvailable, latest, err := version.IsUpdateAvailable()
+	report.Add(doctor.CheckVersion(version.GetCurrentVersion().Version, updateAvailable, latest, err))
+
+	if ctx.Err() != nil {
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.InterruptedErr, "Diagnostics interrupted", ctx.Err())))
+		return
+	}
+
+	failed := report.Count(doctor.StatusFail)
+	bus.Publish(bus.NewEvent(bus.DoctorFinished, presenter.NewPresenter(report, opts.Option), failed == 0))
+
+	if failed > 0 {
+		msg := fmt.Sprintf("%d of %d checks failed", failed, len(report.Results))
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.DiagnosticsFailedErr, msg, nil)))
+	}
+}
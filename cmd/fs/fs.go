@@ -0,0 +1,22 @@
+// Package fs manages the local filesystem analysis subcommands.
+package fs
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/image"
+)
+
+// Cmd return the command related to local filesystem analysis.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fs",
+		Short: "Commands related to local filesystem analysis",
+		Long: `Commands related to local filesystem analysis.
+A directory, e.g. a build context, an unpacked rootfs or a monorepo checkout,
+is cataloged like an image and sent to the analyzer.`,
+	}
+
+	cmd.AddCommand(image.DirectoryScanCmd())
+
+	return cmd
+}
@@ -0,0 +1,50 @@
+package history
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/diff"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+// DiffCmd will return the command for comparing two scan results in the history.
+func DiffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <old-id> <new-id>",
+		Short: "Compare two scan results in the history",
+		Long:  `Compare the vulnerabilities, packages and layers of two scan results in the history.`,
+		Args:  cobra.ExactArgs(2), // nolint: gomnd
+		Run: func(cmd *cobra.Command, args []string) {
+			go diffHistory(args[0], args[1])
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+}
+
+func diffHistory(oldID, newID string) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The history difference only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	store := newStore()
+
+	oldRecord, err := store.Load(oldID)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	newRecord, err := store.Load(newID)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	report := diff.Compare(oldRecord.Result, newRecord.Result)
+	bus.Publish(bus.NewEvent(bus.DiffFinished, presenter.NewPresenter(report, opts.Option), true))
+}
@@ -0,0 +1,45 @@
+// Package history manages the commands for the local scan history.
+package history
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/history"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+const fullTable = 0
+
+var opts struct {
+	presenter.Option
+
+	// repo is the image repo to filter the history list
+	repo string
+}
+
+// Cmd return the command related to the local scan history.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Browse the local history of scan results",
+		Long: `Browse the local history of scan results.
+Every scan result is kept under the config home, the retention is set
+by the history_max_entries and history_max_age_days config options.`,
+	}
+
+	cmd.AddCommand(ListCmd())
+	cmd.AddCommand(ShowCmd())
+	cmd.AddCommand(DiffCmd())
+
+	cmd.PersistentFlags().StringVarP(
+		&opts.OutputFormat, "output", "o", "table", "output format of the result")
+	cmd.PersistentFlags().IntVar(
+		&opts.Limit, "limit", fullTable, // set to 0 will show all rows
+		"number of rows to show in the report (for table format only)")
+
+	return cmd
+}
+
+func newStore() *history.Store {
+	return history.NewStore(history.DefaultStorePath(config.Config().ConfigHome))
+}
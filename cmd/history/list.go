@@ -0,0 +1,51 @@
+package history
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/history"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+// ListCmd will return the command for listing the scan results in the history.
+func ListCmd() *cobra.Command {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the scan results in the history",
+		Long:  `List the scan results in the history, newest first.`,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			go listHistory()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	listCmd.Flags().StringVar(&opts.repo, "image", "", "only list the scan results of this image repo")
+
+	return listCmd
+}
+
+func listHistory() {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The history list only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	entries, err := newStore().List(opts.repo)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	if len(entries) == 0 {
+		bus.Publish(bus.NewMessageEvent("No scan result found in the history", true))
+		return
+	}
+
+	bus.Publish(bus.NewEvent(bus.PrintHistory,
+		presenter.NewPresenter(&history.Entries{Entries: entries}, opts.Option), true))
+}
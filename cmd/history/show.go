@@ -0,0 +1,32 @@
+package history
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+// ShowCmd will return the command for showing a scan result in the history.
+func ShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a scan result in the history",
+		Long:  `Show a scan result in the history, in any output format of image scan.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			go showHistory(args[0])
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+}
+
+func showHistory(id string) {
+	record, err := newStore().Load(id)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	bus.Publish(bus.NewEvent(bus.ScanFinished, presenter.NewPresenter(record.Result, opts.Option), true))
+}
@@ -0,0 +1,101 @@
+package image
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/attest"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+// addAttestFlags will add the flags of the signed attestation of the sbom to the command, the vulnerability
+// result can only be attested by a scan.
+func addAttestFlags(cmd *cobra.Command, withResult bool) {
+	cmd.PersistentFlags().StringVar(
+		&opts.attestFile, "attest", "",
+		"write the sbom as an in-toto attestation signed in a DSSE envelope to this file")
+	cmd.PersistentFlags().StringVar(
+		&opts.attestKey, "attest-key", "", "the ECDSA or ed25519 private key in PEM signing the attestation")
+	cmd.PersistentFlags().StringVar(
+		&opts.attestFormat, "attest-format", attest.FormatCycloneDX,
+		"format of the attested sbom: cyclonedx or spdx")
+
+	if withResult {
+		cmd.PersistentFlags().BoolVar(
+			&opts.attestResult, "attest-result", false, "also attest the vulnerability result of the scan")
+	}
+}
+
+// checkAttestOptions will check the attestation flags and load the signing key, so that a bad key fails
+// before the image is pulled.
+func checkAttestOptions() (*attest.Signer, error) {
+	if opts.attestFile == "" {
+		if opts.attestKey != "" || opts.attestResult {
+			return nil, cberr.NewError(cberr.ConfigErr, "The attestation flags require the --attest file", nil)
+		}
+
+		return nil, nil
+	}
+
+	if opts.attestKey == "" {
+		return nil, cberr.NewError(cberr.ConfigErr, "The --attest flag requires the --attest-key private key", nil)
+	}
+
+	switch strings.ToLower(opts.attestFormat) {
+	case attest.FormatCycloneDX, attest.FormatSPDX:
+	default:
+		errMsg := fmt.Sprintf("Invalid attestation format %q, must be %s or %s",
+			opts.attestFormat, attest.FormatCycloneDX, attest.FormatSPDX)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	return attest.LoadSigner(opts.attestKey)
+}
+
+// writeAttestation will sign the statement of the sbom, and of the vulnerability result if required, about
+// the image of the manifest digest, and write them to the attestation file.
+func writeAttestation(
+	signer *attest.Signer, fullTag, manifestDigest string, doc bom.JSONDocument, result *image.ScannedImage,
+) error {
+	statement, err := attest.SBOMStatement(fullTag, manifestDigest, opts.attestFormat, doc)
+	if err != nil {
+		return err
+	}
+
+	statements := []*attest.Statement{statement}
+
+	if result != nil && opts.attestResult {
+		statement, err := attest.ScanResultStatement(result)
+		if err != nil {
+			return err
+		}
+
+		statements = append(statements, statement)
+	}
+
+	envelopes := make([]*attest.Envelope, 0, len(statements))
+
+	for _, statement := range statements {
+		envelope, err := attest.Sign(statement, signer)
+		if err != nil {
+			return err
+		}
+
+		envelopes = append(envelopes, envelope)
+	}
+
+	if err := attest.WriteEnvelopes(opts.attestFile, envelopes); err != nil {
+		return err
+	}
+
+	logrus.WithFields(logrus.Fields{"file": opts.attestFile, "keyID": signer.KeyID()}).Info("Attestation written")
+	bus.Publish(bus.NewMessageEvent(
+		fmt.Sprintf("Wrote %d attestations of %s to %s", len(envelopes), fullTag, opts.attestFile), false))
+
+	return nil
+}
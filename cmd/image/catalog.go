@@ -0,0 +1,62 @@
+package image
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/scan"
+)
+
+// addCatalogFlags will add the flags of the cataloging of the packages to the command, a directory has no layers
+// so the scope is only added for the images.
+func addCatalogFlags(cmd *cobra.Command, withScope bool) {
+	if withScope {
+		cmd.PersistentFlags().StringVar(
+			&opts.Catalog.Scope, "scope", "",
+			"layers of the image cataloged: squashed or all-layers, to find the packages removed by a later layer "+
+				"(default squashed)")
+	}
+
+	cmd.PersistentFlags().StringSliceVar(
+		&opts.Catalog.Catalogers, "catalogers", nil,
+		"catalogers enabled, or disabled if prefixed with \"-\", e.g. apk,python or -java (default all the image ones)")
+	cmd.PersistentFlags().StringArrayVar(
+		&opts.Catalog.Exclusions, "exclude", nil,
+		"glob of the paths not cataloged, e.g. **/test/** or /usr/share/doc/** (can be repeated)")
+	cmd.PersistentFlags().StringVar(
+		&opts.Catalog.SearchArchives, "search-archives", "",
+		"archives searched for packages: indexed (jar, zip), all (also tar.gz) or none (default indexed)")
+}
+
+// resolveCatalogOption will fill the cataloging options not set by flags from the profile, and check them.
+func resolveCatalogOption() error {
+	catalog := &opts.Catalog
+
+	if catalog.Scope == "" {
+		catalog.Scope = config.GetConfig(config.CatalogScope)
+	}
+
+	if len(catalog.Catalogers) == 0 {
+		catalog.Catalogers = strings.Fields(config.GetConfig(config.Catalogers))
+	}
+
+	if len(catalog.Exclusions) == 0 {
+		catalog.Exclusions = strings.Fields(config.GetConfig(config.CatalogExclusions))
+	}
+
+	if catalog.SearchArchives == "" {
+		catalog.SearchArchives = config.GetConfig(config.SearchArchives)
+	}
+
+	defaults := scan.DefaultCatalogOption()
+	if catalog.Scope == "" {
+		catalog.Scope = defaults.Scope
+	}
+
+	if catalog.SearchArchives == "" {
+		catalog.SearchArchives = defaults.SearchArchives
+	}
+
+	return catalog.Validate()
+}
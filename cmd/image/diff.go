@@ -0,0 +1,110 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/diff"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+// DiffCmd will return the image diff command.
+func DiffCmd() *cobra.Command {
+	diffCmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare the vulnerabilities, packages and layers of two images",
+		Long: printtool.Tprintf(`Compare the vulnerabilities, packages and layers of two images.
+Each side can be an image or a scan report saved with the json output:
+    {{.appName}} image diff yourrepo/yourimage:v1 yourrepo/yourimage:v2
+    {{.appName}} image diff old-report.json yourrepo/yourimage:v2 --fail-on-new HIGH
+`, map[string]interface{}{
+			"appName": internal.ApplicationName,
+		}),
+		Args: cobra.ExactArgs(2), // nolint: gomnd
+		PreRun: func(cmd *cobra.Command, args []string) {
+			opts.keepLayers = true
+			initScanHandler(cmd, args)
+		},
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				handleDiff(ctx, args[0], args[1])
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	diffCmd.Flags().BoolVar(
+		&opts.ForceScan, "force", false, "trigger a force scan no matter the image is scanned or not")
+	diffCmd.Flags().StringVar(
+		&opts.failOnNew, "fail-on-new", "",
+		"exit with a policy violation if the new image adds vulnerabilities of this severity or above")
+
+	return diffCmd
+}
+
+func handleDiff(ctx context.Context, oldInput, newInput string) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The image difference only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	if opts.failOnNew != "" && !image.IsValidSeverity(opts.failOnNew) {
+		errMsg := fmt.Sprintf("Invalid severity %q for --fail-on-new", opts.failOnNew)
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ConfigErr, errMsg, nil)))
+
+		return
+	}
+
+	oldImage, done := loadOrScan(ctx, oldInput)
+	if done {
+		return
+	}
+
+	newImage, done := loadOrScan(ctx, newInput)
+	if done {
+		return
+	}
+
+	report := diff.Compare(oldImage, newImage)
+
+	var violations []image.Vulnerability
+	if opts.failOnNew != "" {
+		violations = report.NewVulnerabilitiesAtLeast(opts.failOnNew)
+	}
+
+	bus.Publish(bus.NewEvent(bus.DiffFinished, presenter.NewPresenter(report, opts.presenterOption), len(violations) == 0))
+
+	if len(violations) > 0 {
+		errMsg := fmt.Sprintf("%d new vulnerabilities of severity %s or above",
+			len(violations), strings.ToUpper(opts.failOnNew))
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.PolicyViolationErr, errMsg, nil)))
+	}
+}
+
+// loadOrScan will load the input as a saved scan report if it is one, otherwise scan it as an image.
+func loadOrScan(ctx context.Context, input string) (*image.ScannedImage, bool) {
+	if diff.IsReportFile(input) {
+		result, err := diff.LoadReport(input)
+		if err != nil {
+			bus.Publish(bus.NewErrorEvent(err))
+			return nil, true
+		}
+
+		return result, false
+	}
+
+	return actualScan(ctx, input, scanHandler, "", "")
+}
@@ -0,0 +1,112 @@
+package image
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/scan"
+)
+
+// DirectoryScanCmd will return the command scanning a local directory, e.g. a build context or an unpacked rootfs.
+func DirectoryScanCmd() *cobra.Command {
+	scanCmd := &cobra.Command{
+		Use:   "scan <dir>",
+		Short: "Scan a local directory and generate vulnerability report",
+		Long: printtool.Tprintf(`Scan a local directory and generate vulnerability report.
+The directory is identified by a synthetic tag fs/<name>:<digest of its packages>.
+Supports the following directories:
+    {{.appName}} fs scan path/to/build/context
+    {{.appName}} fs scan path/to/rootfs --exclude "**/node_modules/**"
+    {{.appName}} fs scan . --baseline baseline.json --fail-on HIGH
+`, map[string]interface{}{
+			"appName": internal.ApplicationName,
+		}),
+		Args:   cobra.ExactArgs(1),
+		PreRun: initScanHandler,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				handleScan(ctx, scan.DirectoryPrefix+args[0])
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	addReportFlags(scanCmd, "directory")
+	addCatalogFlags(scanCmd, false)
+
+	return scanCmd
+}
+
+// addReportFlags will add the flags of the scan report to a scan command outside of the image commands.
+func addReportFlags(scanCmd *cobra.Command, subject string) {
+	scanCmd.PersistentFlags().StringVarP(
+		&opts.OutputFormat, "output", "o", "table", "output format of the result")
+	scanCmd.PersistentFlags().IntVar(
+		&opts.Timeout, "timeout", defaultTimeout, "set the duration (second) for the scan process")
+	scanCmd.PersistentFlags().BoolVar(
+		&opts.ForceScan, "force", false, fmt.Sprintf("trigger a force scan no matter the %s is scanned or not", subject))
+	scanCmd.PersistentFlags().IntVar(
+		&opts.Limit, "limit", fullTable, // set to 0 will show all rows
+		"number of rows to show in the report (for table format only)")
+	scanCmd.PersistentFlags().StringVar(
+		&opts.baselineFile, "baseline", "", "only report the vulnerabilities not recorded in this baseline file")
+	scanCmd.PersistentFlags().BoolVar(
+		&opts.writeBaseline, "write-baseline", false,
+		fmt.Sprintf("record the current vulnerabilities of the %s to the baseline file", subject))
+	scanCmd.PersistentFlags().StringVar(
+		&opts.failOn, "fail-on", "",
+		"exit with a policy violation if the reported vulnerabilities include this severity or above")
+}
+
+// scanBom will send the sbom of an input without an image, e.g. a directory, to the backend. The digest of the sbom
+// stands for the image id, so an unchanged input reuses the result known by the backend.
+func scanBom(
+	ctx context.Context, generatedBom *scan.Bom, handler *scan.Handler, buildStep, namespace, operationID string,
+) (*image.ScannedImage, bool) {
+	imageID := generatedBom.ManifestDigest
+	if target, ok := generatedBom.Packages.Source.Target.(bom.JSONImageSource); ok && target.ID != "" {
+		imageID = target.ID
+	}
+
+	if !opts.ForceScan && !isCycloneDXOutput() {
+		cacheSpan := metrictool.StartSpan(metrictool.StageCacheLookup)
+		results, err := handler.GetImagesScanResultsFromBackendByImageID(ctx, imageID, version.GetCurrentVersion().Version)
+		cacheSpan.SetError(err)
+		cacheSpan.SetAttribute("hit", fmt.Sprint(err == nil))
+		cacheSpan.End()
+
+		if err == nil {
+			metrictool.Add(metrictool.CounterCacheHits, 1)
+			results.Packages = generatedBom.Packages
+
+			return results, false
+		}
+	}
+
+	// there is no layer, the files are only cataloged into the sbom
+	handler.AttachData(generatedBom, []layers.Layer{}, buildStep, namespace, imageID)
+
+	result, err := handler.Scan(ctx, operationID, opts.scanOption)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return nil, true
+	}
+
+	logrus.WithField("fullTag", generatedBom.FullTag).Info("SBOM scanned")
+
+	return result, false
+}
@@ -15,6 +15,39 @@ type (
 var opts struct {
 	scanOption
 	presenterOption
+
+	// baseImagesFile is the catalog of known base images used for base image detection
+	baseImagesFile string
+	// remediation is whether to show the remediation advice after the scan result
+	remediation bool
+	// dockerfile is whether to add a Dockerfile snippet to the remediation advice
+	dockerfile bool
+	// keepLayers is whether the result must carry the image layers, even if it is cached in the backend
+	keepLayers bool
+	// baselineFile is the baseline of known vulnerabilities, which are not reported
+	baselineFile string
+	// writeBaseline is whether to record the current vulnerabilities to the baseline file
+	writeBaseline bool
+	// failOn is the severity threshold of reported vulnerabilities failing the scan
+	failOn string
+	// failOnNew is the severity threshold of new vulnerabilities failing the diff
+	failOnNew string
+	// attestFile is the file of the signed attestations of the sbom and the scan result
+	attestFile string
+	// attestKey is the private key signing the attestations
+	attestKey string
+	// attestFormat is the format of the attested sbom, cyclonedx or spdx
+	attestFormat string
+	// attestResult is whether to also attest the vulnerability result
+	attestResult bool
+	// pushReferrers is whether to push the sbom and the report as referrers of the image
+	pushReferrers bool
+	// referrersFormat is the format of the sbom pushed as referrer, cyclonedx or spdx
+	referrersFormat string
+	// artifactType is the artifact type of the referrers listed
+	artifactType string
+	// fetchDir is the directory the content of the listed referrers is written to
+	fetchDir string
 }
 
 const (
@@ -34,6 +67,9 @@ func Cmd() *cobra.Command {
 	cmd.AddCommand(ValidateCmd())
 	cmd.AddCommand(PackagesCmd())
 	cmd.AddCommand(PayloadCmd())
+	cmd.AddCommand(RemediateCmd())
+	cmd.AddCommand(DiffCmd())
+	cmd.AddCommand(ReferrersCmd())
 
 	cmd.PersistentFlags().StringVarP(
 		&opts.OutputFormat, "output", "o", "table", "output format of the result")
@@ -48,6 +84,10 @@ func Cmd() *cobra.Command {
 		&opts.Credential, "cred", "", "use `USERNAME[:PASSWORD]` for accessing the registry")
 	cmd.PersistentFlags().IntVar(
 		&opts.Timeout, "timeout", defaultTimeout, "set the duration (second) for the scan process")
+	cmd.PersistentFlags().StringVar(
+		&opts.baseImagesFile, "base-images", "",
+		"the catalog of known base images (default is base_images.yaml under the config home)")
+	addCatalogFlags(cmd, true)
 
 	return cmd
 }
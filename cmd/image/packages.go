@@ -1,6 +1,7 @@
 package image
 
 import (
+	"context"
 	"fmt"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -8,6 +9,8 @@ import (
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/attest"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
@@ -22,27 +25,62 @@ func PackagesCmd() *cobra.Command {
 		Long: printtool.Tprintf(`Download an image and print the image packages:
     {{.appName}} image packages yourrepo/yourimage:tag
     {{.appName}} image packages path/to/yourimage.tar
+    {{.appName}} image packages dir:path/to/checkout
+    {{.appName}} image packages yourrepo/yourimage:tag --attest sbom.intoto.jsonl --attest-key key.pem
 `, map[string]interface{}{
 			"appName": internal.ApplicationName,
 		}),
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			go PrintSBOM(args[0])
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				PrintSBOM(ctx, args[0])
+			}()
 			terminalui.NewDisplay().DisplayEvents()
 		},
 	}
 
+	addAttestFlags(packagesCmd, false)
+
 	return packagesCmd
 }
 
 // PrintSBOM will print the image SBOM.
-func PrintSBOM(input string) {
+func PrintSBOM(ctx context.Context, input string) {
 	var msg string
 
+	if err := resolveCatalogOption(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	signer, err := checkAttestOptions()
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	if scan.IsDirectoryInput(input) {
+		generatedBom, hasErr := scan.NewScanner().ExtractDataFromDirectory(ctx, input, opts.scanOption)
+		if !hasErr {
+			publishSBOM(signer, generatedBom)
+		}
+
+		return
+	}
+
 	registryHandler := scan.NewRegistryHandler()
 	scanner := scan.NewScanner()
 
-	img, err := registryHandler.LoadImage(input, opts.scanOption)
+	img, err := registryHandler.LoadImage(ctx, input, opts.scanOption)
+	if ctx.Err() != nil {
+		scan.Cleanup()
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.InterruptedErr, "Image pull interrupted", ctx.Err())))
+
+		return
+	}
+
 	if err != nil {
 		msg := fmt.Sprintf("Failed to pull image for input %s", input)
 		e := cberr.NewError(cberr.ImageLoadErr, msg, err)
@@ -71,6 +109,19 @@ func PrintSBOM(input string) {
 		return
 	}
 
+	publishSBOM(signer, generatedBom)
+}
+
+// publishSBOM will publish all the packages of the sbom to the bus, after writing its attestation if required.
+func publishSBOM(signer *attest.Signer, generatedBom *scan.Bom) {
+	if signer != nil {
+		err := writeAttestation(signer, generatedBom.FullTag, generatedBom.ManifestDigest, generatedBom.Packages, nil)
+		if err != nil {
+			bus.Publish(bus.NewErrorEvent(err))
+			return
+		}
+	}
+
 	sbomImage := image.SBOM{
 		FullTag:        generatedBom.FullTag,
 		ManifestDigest: generatedBom.ManifestDigest,
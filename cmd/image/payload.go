@@ -1,9 +1,11 @@
 package image
 
 import (
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/scan"
@@ -15,7 +17,11 @@ func PayloadCmd() *cobra.Command {
 		Use:  "payload <source>",
 		Args: cobra.ExactArgs(1),
 		Run: func(cmd *cobra.Command, args []string) {
-			go printPayload(args[0])
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				printPayload(ctx, args[0])
+			}()
 			terminalui.NewDisplay().DisplayEvents()
 		},
 	}
@@ -25,9 +31,14 @@ func PayloadCmd() *cobra.Command {
 }
 
 // printPayload will print the scan payload.
-func printPayload(input string) {
+func printPayload(ctx context.Context, input string) {
+	if err := resolveCatalogOption(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
 	scanner := scan.NewScanner()
-	generatedBom, imgLayers, err := scanner.ExtractDataFromImage(input, opts.scanOption)
+	generatedBom, imgLayers, err := scanner.ExtractDataFromImage(ctx, input, opts.scanOption)
 	if err {
 		return
 	}
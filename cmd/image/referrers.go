@@ -0,0 +1,264 @@
+package image
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	stereoimage "github.com/anchore/stereoscope/pkg/image"
+	"github.com/google/go-containerregistry/pkg/name"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/attest"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/referrers"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/scan"
+)
+
+const (
+	permModeDir       = 0700
+	permModeReadWrite = 0600
+)
+
+// ReferrersCmd will return the command listing and fetching the artifacts referencing an image.
+func ReferrersCmd() *cobra.Command {
+	referrersCmd := &cobra.Command{
+		Use:   "referrers <image>",
+		Short: "List and fetch the artifacts referencing an image",
+		Long: printtool.Tprintf(`List the artifacts referencing an image in its registry, e.g. the sbom and the report
+pushed by the --push-referrers flag of the scan, and fetch them:
+    {{.appName}} image referrers yourrepo/yourimage:tag
+    {{.appName}} image referrers yourrepo/yourimage@sha256:<manifest digest> --fetch path/to/dir
+    {{.appName}} image referrers yourrepo/yourimage:tag --artifact-type application/spdx+json --fetch .
+The referrers are read from the OCI 1.1 referrers api, or from the referrers tag for the other registries.
+`, map[string]interface{}{
+			"appName": internal.ApplicationName,
+		}),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				listReferrers(ctx, args[0])
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	referrersCmd.Flags().StringVar(
+		&opts.artifactType, "artifact-type", "", "only list the referrers of this artifact type")
+	referrersCmd.Flags().StringVar(
+		&opts.fetchDir, "fetch", "", "write the content of the listed referrers to this directory")
+
+	return referrersCmd
+}
+
+// addReferrersFlags will add the flags pushing the results of the scan as referrers of the image.
+func addReferrersFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(
+		&opts.pushReferrers, "push-referrers", false,
+		"push the sbom and the json report to the registry as OCI artifacts referencing the image")
+	cmd.PersistentFlags().StringVar(
+		&opts.referrersFormat, "referrers-format", attest.FormatCycloneDX,
+		"format of the sbom pushed as referrer: cyclonedx or spdx")
+}
+
+// checkReferrersOptions will check that the results can be pushed for the input, i.e. an image of a registry.
+func checkReferrersOptions(input string) error {
+	if !opts.pushReferrers {
+		return nil
+	}
+
+	if _, err := sbomArtifactType(); err != nil {
+		return err
+	}
+
+	if _, err := referrersRepository(input); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// pushReferrers will push the sbom and the json report of the result as referrers of the scanned image,
+// with the same credentials as the image pull.
+func pushReferrers(ctx context.Context, input string, result *image.ScannedImage) error {
+	repo, err := referrersRepository(input)
+	if err != nil {
+		return err
+	}
+
+	client := referrers.NewClient(opts.Authenticator())
+
+	// an image of the daemon is found in the registry by its repo digest
+	digests := []string{result.ManifestDigest}
+	for _, repoDigest := range result.RepoDigests {
+		if parts := strings.SplitN(repoDigest, "@", 2); len(parts) == 2 { // nolint: gomnd
+			digests = append(digests, parts[1])
+		}
+	}
+
+	subject, err := client.FindSubject(ctx, repo, digests...)
+	if err != nil {
+		return cberr.NewError(cberr.ReferrersErr, "Failed to find the scanned image in the registry", err)
+	}
+
+	artifactType, _ := sbomArtifactType()
+
+	sbomContent, _, err := attest.EncodeSBOM(result.Packages, opts.referrersFormat)
+	if err != nil {
+		return err
+	}
+
+	report, err := json.MarshalIndent(result, "", " ")
+	if err != nil {
+		return cberr.NewError(cberr.ReferrersErr, "Failed to encode the scan report", err)
+	}
+
+	artifacts := []referrers.Artifact{
+		{ArtifactType: artifactType, Title: "sbom." + opts.referrersFormat + ".json", Content: sbomContent},
+		{ArtifactType: referrers.ArtifactTypeScanReport, Title: "report.json", Content: report},
+	}
+
+	for _, artifact := range artifacts {
+		desc, err := client.Push(ctx, subject, artifact)
+		if err != nil {
+			errMsg := fmt.Sprintf("Failed to push %s as referrer of %s", artifact.Title, subject)
+			return cberr.NewError(cberr.ReferrersErr, errMsg, err)
+		}
+
+		logrus.WithFields(logrus.Fields{"subject": subject.String(), "referrer": desc.Digest}).Info("Referrer pushed")
+	}
+
+	bus.Publish(bus.NewMessageEvent(fmt.Sprintf("Pushed %d referrers of %s", len(artifacts), subject), false))
+
+	return nil
+}
+
+func listReferrers(ctx context.Context, input string) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The referrers list only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	client := referrers.NewClient(opts.Authenticator())
+
+	subject, err := client.ResolveSubject(ctx, input)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to read the image %s from the registry", input)
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ReferrersErr, errMsg, err)))
+
+		return
+	}
+
+	listed, err := client.List(ctx, subject)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to list the referrers of %s", subject)
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.ReferrersErr, errMsg, err)))
+
+		return
+	}
+
+	report := &referrers.Report{Subject: subject.String(), Referrers: []referrers.Referrer{}}
+
+	for _, desc := range listed {
+		if opts.artifactType != "" && desc.ArtifactType != opts.artifactType {
+			continue
+		}
+
+		referrer := referrers.NewReferrer(desc)
+
+		if opts.fetchDir != "" {
+			if referrer.File, err = fetchReferrer(ctx, client, subject.Context(), desc); err != nil {
+				bus.Publish(bus.NewErrorEvent(err))
+				return
+			}
+		}
+
+		report.Referrers = append(report.Referrers, referrer)
+	}
+
+	opts.presenterOption.Limit = len(report.Referrers)
+	bus.Publish(bus.NewEvent(bus.PrintReferrers, presenter.NewPresenter(report, opts.presenterOption), true))
+}
+
+// fetchReferrer will write the content of the referrer to the fetch directory, named after its title or digest.
+func fetchReferrer(
+	ctx context.Context, client *referrers.Client, repo name.Repository, desc imgspecv1.Descriptor,
+) (string, error) {
+	artifact, err := client.Fetch(ctx, repo, desc)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to fetch the referrer %s", desc.Digest)
+		return "", cberr.NewError(cberr.ReferrersErr, errMsg, err)
+	}
+
+	// the title is set by the pusher, only its base name is kept
+	fileName := filepath.Base(artifact.Title)
+	if artifact.Title == "" || fileName == "." || fileName == string(filepath.Separator) {
+		fileName = desc.Digest.Encoded()
+	}
+
+	path := filepath.Join(opts.fetchDir, desc.Digest.Encoded()[:12]+"-"+fileName)
+
+	if err := os.MkdirAll(opts.fetchDir, permModeDir); err != nil {
+		return "", cberr.NewError(cberr.ReferrersErr, fmt.Sprintf("Failed to create %s", opts.fetchDir), err)
+	}
+
+	if err := ioutil.WriteFile(path, artifact.Content, permModeReadWrite); err != nil {
+		return "", cberr.NewError(cberr.ReferrersErr, fmt.Sprintf("Failed to write %s", path), err)
+	}
+
+	return path, nil
+}
+
+// referrersRepository will return the registry repository of the input, the results are pushed only for an image
+// of a registry or of the daemon.
+func referrersRepository(input string) (name.Repository, error) {
+	ref, err := scan.ResolveImageReference(input)
+	if err != nil {
+		return name.Repository{}, err
+	}
+
+	switch ref.Source {
+	case stereoimage.OciRegistrySource, stereoimage.DockerDaemonSource, stereoimage.PodmanDaemonSource:
+	default:
+		errMsg := fmt.Sprintf("The --push-referrers flag requires an image of a registry, not %s", input)
+		return name.Repository{}, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	parsed, err := name.ParseReference(ref.Location)
+	if err != nil {
+		errMsg := fmt.Sprintf("Invalid image reference %s for --push-referrers", ref.Location)
+		return name.Repository{}, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return parsed.Context(), nil
+}
+
+// sbomArtifactType will return the artifact type of the sbom pushed as referrer.
+func sbomArtifactType() (string, error) {
+	switch strings.ToLower(opts.referrersFormat) {
+	case attest.FormatCycloneDX:
+		return referrers.ArtifactTypeCycloneDX, nil
+	case attest.FormatSPDX:
+		return referrers.ArtifactTypeSPDX, nil
+	default:
+		errMsg := fmt.Sprintf("Invalid referrers format %q, must be %s or %s",
+			opts.referrersFormat, attest.FormatCycloneDX, attest.FormatSPDX)
+		return "", cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+}
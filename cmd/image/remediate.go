@@ -0,0 +1,69 @@
+package image
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/remediation"
+)
+
+// RemediateCmd will return the image remediate command.
+func RemediateCmd() *cobra.Command {
+	remediateCmd := &cobra.Command{
+		Use:   "remediate <source>",
+		Short: "Scan an image and generate package upgrade commands",
+		Long: printtool.Tprintf(`Scan an image and generate the package upgrade commands fixing its vulnerabilities.
+Supports the following image sources:
+    {{.appName}} image remediate yourrepo/yourimage:tag
+    {{.appName}} image remediate path/to/yourimage.tar --dockerfile
+`, map[string]interface{}{
+			"appName": internal.ApplicationName,
+		}),
+		Args:   cobra.ExactArgs(1),
+		PreRun: initScanHandler,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				handleRemediate(ctx, args[0])
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	remediateCmd.Flags().BoolVar(
+		&opts.ForceScan, "force", false, "trigger a force scan no matter the image is scanned or not")
+	remediateCmd.Flags().BoolVar(
+		&opts.dockerfile, "dockerfile", false, "add a Dockerfile snippet to the remediation advice")
+
+	return remediateCmd
+}
+
+func handleRemediate(ctx context.Context, input string) {
+	result, done := actualScan(ctx, input, scanHandler, "", "")
+	if done {
+		return
+	}
+
+	publishRemediation(result, true)
+}
+
+// publishRemediation will publish the remediation advice of the scanned image.
+func publishRemediation(result *image.ScannedImage, isEnd bool) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The remediation advice only supports table and json output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	advice := remediation.NewAdvice(result, opts.dockerfile)
+	bus.Publish(bus.NewEvent(bus.RemediationFinished, presenter.NewPresenter(advice, opts.presenterOption), isEnd))
+}
@@ -0,0 +1,46 @@
+package image
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/scan"
+)
+
+// SBOMScanCmd will return the command scanning an existing sbom file, e.g. one generated at build time.
+func SBOMScanCmd() *cobra.Command {
+	scanCmd := &cobra.Command{
+		Use:   "scan <file>",
+		Short: "Scan an existing SBOM and generate vulnerability report",
+		Long: printtool.Tprintf(`Scan an existing SBOM and generate vulnerability report, without pulling the image.
+Supports syft json, SPDX 2.x json or tag-value and CycloneDX json or xml:
+    {{.appName}} sbom scan sbom.syft.json
+    {{.appName}} sbom scan sbom.cdx.xml --full-tag yourrepo/yourimage:tag --digest sha256:<manifest digest>
+    {{.appName}} sbom scan sbom.spdx.json --baseline baseline.json --fail-on HIGH
+The image is identified by the metadata of the SBOM, or by the flags if set.
+The layers of the image are not available in the report.
+`, map[string]interface{}{
+			"appName": internal.ApplicationName,
+		}),
+		Args:   cobra.ExactArgs(1),
+		PreRun: initScanHandler,
+		Run: func(cmd *cobra.Command, args []string) {
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				handleScan(ctx, scan.SBOMPrefix+args[0])
+			}()
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	scanCmd.PersistentFlags().StringVar(
+		&opts.FullTag, "full-tag", "", "the tag of the image described by the SBOM, e.g. yourrepo/yourimage:tag")
+	scanCmd.PersistentFlags().StringVar(
+		&opts.ManifestDigest, "digest", "", "the manifest digest of the image described by the SBOM")
+	addReportFlags(scanCmd, "SBOM")
+
+	return scanCmd
+}
@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
 	"strings"
+	"time"
 
 	progress "github.com/wagoodman/go-progress"
 
+	stereoimage "github.com/anchore/stereoscope/pkg/image"
 	containersimage "github.com/containers/image/v5/image"
-	"github.com/containers/image/v5/transports/alltransports"
 	imagetype "github.com/containers/image/v5/types"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/client"
@@ -22,7 +23,13 @@ import (
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/baseimage"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/baseline"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/history"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/scan"
@@ -39,23 +46,24 @@ func ScanCmd() *cobra.Command {
 Supports the following image sources:
     {{.appName}} image scan yourrepo/yourimage:tag
     {{.appName}} image scan path/to/yourimage.tar
+    {{.appName}} image scan docker-archive:path/to/images.tar:yourrepo/yourimage:tag
+    {{.appName}} image scan oci-dir:path/to/layout:@1
+    {{.appName}} image scan dir:path/to/rootfs
+    {{.appName}} image scan sbom:path/to/sbom.cdx.json
+    {{.appName}} image scan yourrepo/yourimage:tag --baseline baseline.json --fail-on HIGH
+    {{.appName}} image scan yourrepo/yourimage:tag --attest image.intoto.jsonl --attest-key key.pem --attest-result
+    {{.appName}} image scan yourrepo/yourimage:tag --push-referrers --referrers-format spdx
 `, map[string]interface{}{
 			"appName": internal.ApplicationName,
 		}),
-		Args: cobra.ExactArgs(1),
-		PreRun: func(_ *cobra.Command, _ []string) {
-			saasURL := config.GetConfig(config.SaasURL)
-			orgKey := config.GetConfig(config.OrgKey)
-			apiID := config.GetConfig(config.CBApiID)
-			apiKey := config.GetConfig(config.CBApiKey)
-
-			scanHandler = scan.NewScanHandler(saasURL, orgKey, apiID, apiKey, nil, nil)
-			if err := scanHandler.HealthCheck(); err != nil {
-				bus.Publish(bus.NewErrorEvent(err))
-			}
-		},
+		Args:   cobra.ExactArgs(1),
+		PreRun: initScanHandler,
 		Run: func(cmd *cobra.Command, args []string) {
-			go handleScan(args[0])
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				handleScan(ctx, args[0])
+			}()
 			terminalui.NewDisplay().DisplayEvents()
 		},
 	}
@@ -65,20 +73,168 @@ Supports the following image sources:
 	scanCmd.PersistentFlags().IntVar(
 		&opts.Limit, "limit", fullTable, // set to 0 will show all rows
 		"number of rows to show in the report (for table format only)")
+	scanCmd.PersistentFlags().BoolVar(
+		&opts.remediation, "remediation", false, "show the package upgrades fixing the vulnerabilities after the report")
+	scanCmd.PersistentFlags().BoolVar(
+		&opts.dockerfile, "dockerfile", false, "add a Dockerfile snippet to the remediation advice")
+	scanCmd.PersistentFlags().StringVar(
+		&opts.baselineFile, "baseline", "", "only report the vulnerabilities not recorded in this baseline file")
+	scanCmd.PersistentFlags().BoolVar(
+		&opts.writeBaseline, "write-baseline", false,
+		"record the current vulnerabilities of the image repo to the baseline file")
+	scanCmd.PersistentFlags().StringVar(
+		&opts.failOn, "fail-on", "",
+		"exit with a policy violation if the reported vulnerabilities include this severity or above")
+	addAttestFlags(scanCmd, true)
+	addReferrersFlags(scanCmd)
 
 	return scanCmd
 }
 
-func handleScan(input string) {
-	result, done := actualScan(input, scanHandler, "", "")
+// initScanHandler will create the scan handler from the active profile and check the backend health.
+func initScanHandler(_ *cobra.Command, _ []string) {
+	saasURL := config.GetConfig(config.SaasURL)
+	orgKey := config.GetConfig(config.OrgKey)
+	apiID := config.GetConfig(config.CBApiID)
+	apiKey := config.GetConfig(config.CBApiKey)
+
+	scanHandler = scan.NewScanHandler(saasURL, orgKey, apiID, apiKey, nil, nil)
+	if err := config.CredentialError(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+	} else if err := scanHandler.HealthCheck(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+	}
+}
+
+func handleScan(ctx context.Context, input string) {
+	if err := checkScanGateOptions(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	signer, err := checkAttestOptions()
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	if err := checkReferrersOptions(input); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	result, done := actualScan(ctx, input, scanHandler, "", "")
 	if done {
 		return
 	}
 
-	bus.Publish(bus.NewEvent(bus.ScanFinished, presenter.NewPresenter(result, opts.presenterOption), true))
+	// only the scans are recorded, not the validations, diffs and remediations scanning the image
+	saveToHistory(result)
+
+	// the attestation holds the whole result, before the known vulnerabilities are removed by the baseline
+	if signer != nil {
+		if err := writeAttestation(signer, result.FullTag, result.ManifestDigest, result.Packages, result); err != nil {
+			bus.Publish(bus.NewErrorEvent(err))
+			return
+		}
+	}
+
+	if opts.pushReferrers {
+		if err := pushReferrers(ctx, input, result); err != nil {
+			bus.Publish(bus.NewErrorEvent(err))
+			return
+		}
+	}
+
+	if opts.baselineFile != "" {
+		if _, err := applyBaseline(result); err != nil {
+			bus.Publish(bus.NewErrorEvent(err))
+			return
+		}
+	}
+
+	violations := 0
+
+	if opts.failOn != "" {
+		for _, vul := range result.Vulnerabilities {
+			if vul.IsSeverityAtLeast(opts.failOn) {
+				violations++
+			}
+		}
+	}
+
+	isEnd := !opts.remediation && violations == 0
+	bus.Publish(bus.NewEvent(bus.ScanFinished, presenter.NewPresenter(result, opts.presenterOption), isEnd))
+
+	if opts.remediation {
+		publishRemediation(result, violations == 0)
+	}
+
+	if violations > 0 {
+		errMsg := fmt.Sprintf("%d vulnerabilities of severity %s or above", violations, strings.ToUpper(opts.failOn))
+		bus.Publish(bus.NewErrorEvent(cberr.NewError(cberr.PolicyViolationErr, errMsg, nil)))
+	}
 }
 
-func actualScan(input string, handler *scan.Handler, buildStep, namespace string) (*image.ScannedImage, bool) {
+func checkScanGateOptions() error {
+	if opts.failOn == "" {
+		opts.failOn = config.GetConfig(config.FailOn)
+	}
+
+	if err := checkBaselineOptions(); err != nil {
+		return err
+	}
+
+	if opts.failOn != "" && !image.IsValidSeverity(opts.failOn) {
+		errMsg := fmt.Sprintf("Invalid severity %q for --fail-on", opts.failOn)
+		return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	return nil
+}
+
+func checkBaselineOptions() error {
+	if opts.baselineFile == "" {
+		opts.baselineFile = config.GetConfig(config.BaselineFile)
+	}
+
+	if opts.writeBaseline && opts.baselineFile == "" {
+		return cberr.NewError(cberr.ConfigErr, "The --write-baseline flag requires the --baseline file", nil)
+	}
+
+	return nil
+}
+
+// applyBaseline will either record the result to the baseline file, or remove the recorded findings from the result.
+func applyBaseline(result *image.ScannedImage) (*baseline.Baseline, error) {
+	recorded, err := baseline.Load(opts.baselineFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.writeBaseline {
+		recorded.Record(result)
+
+		if err := recorded.Save(); err != nil {
+			return nil, err
+		}
+
+		msg := fmt.Sprintf("Recorded %d vulnerabilities of %s to baseline %s",
+			len(result.Vulnerabilities), baseline.RepoKey(result.Identifier), opts.baselineFile)
+		bus.Publish(bus.NewMessageEvent(msg, false))
+	}
+
+	recorded.Apply(result)
+
+	return recorded, nil
+}
+
+// actualScan will scan the input and publish the errors to the bus, it returns true if the scan is done with errors.
+//
+// Once ctx is done, e.g. on ^C, the scan is aborted and the temporary files are cleaned up.
+func actualScan(
+	ctx context.Context, input string, handler *scan.Handler, buildStep, namespace string,
+) (*image.ScannedImage, bool) {
 	stage := &progress.Stage{Current: "Fetch image id"}
 	prog := &progress.Manual{}
 	prog.SetTotal(1)
@@ -95,13 +251,73 @@ func actualScan(input string, handler *scan.Handler, buildStep, namespace string
 	operationID := uuid.New().String()
 	logrus.WithField("operation_id", operationID).Info("Starting an operation")
 
-	imageID, err := getImageID(input)
-	if imageID != "" && !opts.ForceScan && opts.presenterOption.OutputFormat != "cyclondx" {
+	catalog, err := loadBaseImageCatalog()
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return nil, true
+	}
+
+	if err := resolveCatalogOption(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return nil, true
+	}
+
+	// the results known by the backend are cataloged with the default option, so they can not be reused
+	if !opts.Catalog.IsDefault() {
+		opts.ForceScan = true
+	}
+
+	// the directories and the sbom files have no image to read, so their sbom is scanned directly
+	if scan.IsDirectoryInput(input) || scan.IsSBOMInput(input) {
+		stage.Current = "Input has no image id"
+
+		var generatedBom *scan.Bom
+
+		var hasErr bool
+
+		scanner := scan.NewScanner()
+		if scan.IsDirectoryInput(input) {
+			generatedBom, hasErr = scanner.ExtractDataFromDirectory(ctx, input, opts.scanOption)
+		} else {
+			generatedBom, hasErr = scanner.ExtractDataFromSBOMFile(input, opts.scanOption)
+		}
+
+		if hasErr {
+			return nil, true
+		}
+
+		return scanBom(ctx, generatedBom, handler, buildStep, namespace, operationID)
+	}
+
+	// the base image detection and the layers need the local data, so a cached result is only returned directly
+	// if none of them is required; the image is then still pulled and cataloged in full, as the base image detection
+	// reads the layers of the packages from the sbom, not only the layer diff ids
+	needsLocalData := !catalog.IsEmpty() || opts.keepLayers || opts.attestFile != "" || opts.pushReferrers
+
+	var cachedResult *image.ScannedImage
+
+	span := metrictool.StartSpan(metrictool.StageImageIDLookup)
+	imageID, err := getImageID(ctx, input)
+	span.SetError(err)
+	span.End()
+
+	if imageID != "" && !opts.ForceScan && !isCycloneDXOutput() {
 		if err == nil {
 			versionInfo := version.GetCurrentVersion()
-			results, err := handler.GetImagesScanResultsFromBackendByImageID(imageID, versionInfo.Version)
+			cacheSpan := metrictool.StartSpan(metrictool.StageCacheLookup)
+			results, err := handler.GetImagesScanResultsFromBackendByImageID(ctx, imageID, versionInfo.Version)
+			cacheSpan.SetError(err)
+			cacheSpan.SetAttribute("hit", fmt.Sprint(err == nil))
+			cacheSpan.End()
+
 			if err == nil {
-				return results, false
+				metrictool.Add(metrictool.CounterCacheHits, 1)
+
+				if !needsLocalData {
+					return results, false
+				}
+
+				cachedResult = results
 			}
 		}
 	}
@@ -109,19 +325,11 @@ func actualScan(input string, handler *scan.Handler, buildStep, namespace string
 	stage.Current = "Done fetching image id"
 
 	scanner := scan.NewScanner()
-	generatedBom, imgLayers, hasErr := scanner.ExtractDataFromImage(input, opts.scanOption)
+	generatedBom, imgLayers, hasErr := scanner.ExtractDataFromImage(ctx, input, opts.scanOption)
 	if hasErr {
 		return nil, true
 	}
 
-	handler.AttachData(generatedBom, imgLayers, buildStep, namespace, imageID)
-
-	result, err := handler.Scan(operationID, opts.scanOption)
-	if err != nil {
-		bus.Publish(bus.NewErrorEvent(err))
-		return nil, true
-	}
-
 	if opts.ShouldCleanup {
 		defer func() {
 			// delete docker image by docker client
@@ -131,19 +339,95 @@ func actualScan(input string, handler *scan.Handler, buildStep, namespace string
 		}()
 	}
 
+	if cachedResult != nil {
+		catalog.Annotate(cachedResult, generatedBom.Packages, imgLayers)
+		cachedResult.Packages = generatedBom.Packages
+		cachedResult.Layers = image.NewLayers(imgLayers)
+
+		return cachedResult, false
+	}
+
+	handler.AttachData(generatedBom, imgLayers, buildStep, namespace, imageID)
+
+	result, err := handler.Scan(ctx, operationID, opts.scanOption)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return nil, true
+	}
+
+	catalog.Annotate(result, generatedBom.Packages, imgLayers)
+	result.Layers = image.NewLayers(imgLayers)
+
 	return result, false
 }
 
-func getImageID(input string) (string, error) {
-	ctx := context.Background()
+// isCycloneDXOutput returns true if the result is presented as a cyclonedx sbom, which needs the packages cataloged
+// locally, so the results known by the backend are not reused.
+func isCycloneDXOutput() bool {
+	format := opts.presenterOption.OutputFormat
+	return format == "cyclonedx" || format == "c"
+}
+
+// loadBaseImageCatalog will load the base image catalog from the flag or the config home.
+func loadBaseImageCatalog() (*baseimage.Catalog, error) {
+	path := opts.baseImagesFile
+	if path == "" {
+		path = baseimage.DefaultCatalogPath(config.Config().ConfigHome)
+	}
+
+	return baseimage.LoadCatalog(path)
+}
+
+// saveToHistory will keep the scan result in the local history, a failure only logs a warning.
+func saveToHistory(result *image.ScannedImage) {
+	store := history.NewStore(history.DefaultStorePath(config.Config().ConfigHome))
+
+	entry, err := store.Save(result, config.GetConfig(config.ActiveUserProfile), time.Now())
+	if err != nil {
+		logrus.WithError(err).Warn("Failed to save scan result to the history")
+		return
+	}
+
+	logrus.Debugf("Scan result saved to the history as %s", entry.ID)
+
+	retention := history.ParseRetention(
+		config.GetConfig(config.HistoryMaxEntries), config.GetConfig(config.HistoryMaxAgeDays))
+	if _, err := store.Prune(retention, time.Now()); err != nil {
+		logrus.WithError(err).Warn("Failed to prune the history")
+	}
+}
+
+// getImageID will read the config digest of the image, from the same source as the image load.
+func getImageID(ctx context.Context, input string) (string, error) {
+	ref, err := scan.ResolveImageReference(input)
+	if err != nil {
+		return "", err
+	}
+
+	imageID, err := readImageID(ctx, ref)
+	if err != nil && !ref.Explicit && ref.Source == stereoimage.DockerDaemonSource {
+		// the image missing from the daemon is pulled from the registry by the image load
+		ref.Source = stereoimage.OciRegistrySource
+		imageID, err = readImageID(ctx, ref)
+	}
+
+	return imageID, err
+}
+
+func readImageID(ctx context.Context, ref scan.ImageReference) (string, error) {
+	// if a multi-arch image detected, pull the linux image by default
 	srcCtx := &imagetype.SystemContext{
-		// if a multi-arch image detected, pull the linux image by default
 		ArchitectureChoice:          "amd64",
 		OSChoice:                    "linux",
 		DockerInsecureSkipTLSVerify: imagetype.OptionalBoolTrue,
 	}
 
-	src, err := parseImageSource(ctx, srcCtx, input)
+	imageRef, err := ref.ContainersReference()
+	if err != nil {
+		return "", err
+	}
+
+	src, err := imageRef.NewImageSource(ctx, srcCtx)
 	if err != nil {
 		return "", err
 	}
@@ -173,19 +457,3 @@ func getImageID(input string) (string, error) {
 
 	return configDigest, nil
 }
-
-// parseImageSource converts image URL-like string to an ImageSource.
-// The caller must call .Close() on the returned ImageSource.
-func parseImageSource(ctx context.Context, srcCtx *imagetype.SystemContext, name string) (imagetype.ImageSource, error) {
-	transport := alltransports.TransportFromImageName(name)
-	if transport == nil && !strings.Contains(name, ".tar") {
-		name = "docker://" + name
-	}
-
-	ref, err := alltransports.ParseImageName(name)
-	if err != nil {
-		return nil, err
-	}
-
-	return ref.NewImageSource(ctx, srcCtx)
-}
@@ -1,6 +1,7 @@
 package image
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/spf13/cobra"
@@ -9,6 +10,7 @@ import (
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/printtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/signaltool"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/tabletool"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
@@ -34,6 +36,9 @@ func ValidateCmd() *cobra.Command {
 Supports the following image sources:
     {{.appName}} image validate yourrepo/yourimage:tag
     {{.appName}} image validate path/to/yourimage.tar
+
+The violations of the vulnerabilities recorded in the baseline are not reported:
+    {{.appName}} image validate yourrepo/yourimage:tag --baseline baseline.json
 `, map[string]interface{}{
 			"appName": internal.ApplicationName,
 		}),
@@ -46,16 +51,25 @@ Supports the following image sources:
 			if buildStep == "" {
 				buildStep = config.GetConfig(config.DefaultBuildStep)
 			}
+			if namespace == "" {
+				namespace = config.GetConfig(config.Namespace)
+			}
 
 			validateImageHandler = validate.NewImageValidateHandler(saasURL, orgKey, apiID, apiKey, buildStep, namespace, "")
 
 			validateScanHandler = scan.NewScanHandler(saasURL, orgKey, apiID, apiKey, nil, nil)
-			if err := validateScanHandler.HealthCheck(); err != nil {
+			if err := config.CredentialError(); err != nil {
+				bus.Publish(bus.NewErrorEvent(err))
+			} else if err := validateScanHandler.HealthCheck(); err != nil {
 				bus.Publish(bus.NewErrorEvent(err))
 			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
-			go handleValidate(args[0])
+			ctx, stop := signaltool.InterruptContext()
+			go func() {
+				defer stop()
+				handleValidate(ctx, args[0])
+			}()
 			terminalui.NewDisplay().DisplayEvents()
 		},
 	}
@@ -69,18 +83,28 @@ Supports the following image sources:
 	validateCmd.PersistentFlags().IntVar(
 		&opts.Limit, "limit", fullTable, // set to 0 will show all rows
 		"number of rows to show in the report (for table format only)")
+	validateCmd.PersistentFlags().StringVar(
+		&opts.baselineFile, "baseline", "", "only report the violations of the vulnerabilities not in this baseline file")
+	validateCmd.PersistentFlags().BoolVar(
+		&opts.writeBaseline, "write-baseline", false,
+		"record the current vulnerabilities of the image repo to the baseline file")
 
 	return validateCmd
 }
 
-func handleValidate(input string) {
+func handleValidate(ctx context.Context, input string) {
 	err := validate.CheckValidBuildStep(buildStep)
 	if err != nil {
 		bus.Publish(bus.NewErrorEvent(err))
 		return
 	}
 
-	scanResult, done := actualScan(input, validateScanHandler, buildStep, namespace)
+	if err := checkBaselineOptions(); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	scanResult, done := actualScan(ctx, input, validateScanHandler, buildStep, namespace)
 	if done {
 		return
 	}
@@ -93,6 +117,17 @@ func handleValidate(input string) {
 		return
 	}
 
+	// only the violations of the vulnerabilities not accepted in the baseline fail the gate
+	if opts.baselineFile != "" {
+		recorded, err := applyBaseline(scanResult)
+		if err != nil {
+			bus.Publish(bus.NewErrorEvent(err))
+			return
+		}
+
+		result = recorded.ApplyViolations(scanResult.Identifier, result)
+	}
+
 	if len(result) == 0 {
 		bus.Publish(bus.NewEvent(bus.ValidateFinishedSuccessfully,
 			fmt.Sprintf("Validate results for %s finished successfully with no violations", input),
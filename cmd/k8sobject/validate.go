@@ -41,9 +41,15 @@ func ValidateCmd() *cobra.Command {
 			if buildStep == "" {
 				buildStep = config.GetConfig(config.DefaultBuildStep)
 			}
+			if namespace == "" {
+				namespace = config.GetConfig(config.Namespace)
+			}
 
 			validateResourceHandler = validate.NewK8SObjectValidateHandler(
 				saasURL, orgKey, apiID, apiKey, buildStep, namespace, path)
+			if err := config.CredentialError(); err != nil {
+				bus.Publish(bus.NewErrorEvent(err))
+			}
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			go handleValidate()
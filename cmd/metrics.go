@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
+)
+
+const otlpExportTimeout = 10 * time.Second
+
+var writeMetricsOnce sync.Once
+
+// writeMetrics will write the metrics of the command to the metrics file and export them to the OTLP endpoint,
+// if set by user. It is called once the command is done, or before exiting with an error.
+func writeMetrics() {
+	writeMetricsOnce.Do(func() {
+		path := viper.GetString("metrics-file")
+
+		endpoint := viper.GetString("otlp-endpoint")
+		if endpoint == "" {
+			endpoint = os.Getenv(metrictool.OTLPEndpointEnv)
+		}
+
+		if path == "" && endpoint == "" {
+			return
+		}
+
+		report := metrictool.Snapshot()
+
+		if path != "" {
+			if err := report.WriteFile(path, viper.GetString("metrics-format")); err != nil {
+				// the ui is already closed at this point
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to write the metrics to %s: %v\n", path, err)
+				logrus.WithError(err).Errorf("Failed to write the metrics to %s", path)
+			}
+		}
+
+		if endpoint != "" {
+			if err := exportMetrics(report, endpoint); err != nil {
+				_, _ = fmt.Fprintf(os.Stderr, "Failed to export the metrics to %s: %v\n", endpoint, err)
+				logrus.WithError(err).Errorf("Failed to export the metrics to %s", endpoint)
+			}
+		}
+	})
+}
+
+// exportMetrics will send the metrics as spans to the OTLP endpoint, with the proxy and tls settings of the cli.
+func exportMetrics(report metrictool.Report, endpoint string) error {
+	transport, err := httptool.NewTransport(httptool.DefaultTransportOptions())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), otlpExportTimeout)
+	defer cancel()
+
+	return report.ExportOTLP(ctx, &http.Client{Transport: transport}, endpoint)
+}
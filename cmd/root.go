@@ -9,6 +9,7 @@ import (
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/memorytool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 )
@@ -20,12 +21,15 @@ var rootCmd = &cobra.Command{
 	Use:   internal.ApplicationName,
 	Short: "Carbon Black's instrumentation client",
 	Long:  `A client CLI for image scanning, and instrumenting Carbon Black services.`,
-	PersistentPreRun: func(_ *cobra.Command, _ []string) {
+	PersistentPreRun: func(cmd *cobra.Command, _ []string) {
+		metrictool.Start(cmd.CommandPath())
 		go memorytool.ReadMemoryStats(ctx)
 		checkNewVersion()
 	},
 	PersistentPostRunE: func(_ *cobra.Command, _ []string) error {
 		cancel()
+		writeMetrics()
+
 		return config.PersistConfig()
 	},
 }
@@ -0,0 +1,21 @@
+// Package sbom manages the subcommands of the existing SBOMs.
+package sbom
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/cmd/image"
+)
+
+// Cmd return the command related to the existing SBOMs.
+func Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sbom",
+		Short: "Commands related to existing SBOMs",
+		Long: `Commands related to existing SBOMs.
+An SBOM generated at build time is analyzed without pulling and cataloging the image again.`,
+	}
+
+	cmd.AddCommand(image.SBOMScanCmd())
+
+	return cmd
+}
@@ -11,20 +11,33 @@ import (
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
 )
 
 // ListCmd will return the user list sub command.
 func ListCmd() *cobra.Command {
-	return &cobra.Command{
+	var opts presenter.Option
+
+	listCmd := &cobra.Command{
 		Use:   "list",
 		Short: "Manage Carbon Black user profile",
-		Long:  `Show all the user profiles and select active user profile.`,
-		Args:  cobra.NoArgs,
+		Long: `Show all the user profiles and select active user profile.
+With --output, the user profiles are only listed, without the selection prompt.`,
+		Args: cobra.NoArgs,
 		Run: func(cmd *cobra.Command, args []string) {
-			go selectUser()
+			if opts.OutputFormat != "" {
+				go listUsers(opts)
+			} else {
+				go selectUser()
+			}
 			terminalui.NewDisplay().DisplayEvents()
 		},
 	}
+
+	listCmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "",
+		"list the user profiles in this output format (table, json or markdown) instead of prompting")
+
+	return listCmd
 }
 
 func selectUser() {
@@ -50,3 +63,15 @@ func selectUser() {
 	msg = fmt.Sprintf("Active user profile selected: %s", profile)
 	bus.Publish(bus.NewMessageEvent(msg, true))
 }
+
+func listUsers(opts presenter.Option) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The user list only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	bus.Publish(bus.NewEvent(bus.PrintProfiles,
+		presenter.NewPresenter(&profileList{Profiles: config.Profiles()}, opts), true))
+}
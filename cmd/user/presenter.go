@@ -0,0 +1,99 @@
+package user
+
+import (
+	"strconv"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+)
+
+const (
+	activeMarker = "*"
+
+	activeHeader    = "Active"
+	nameHeader      = "Name"
+	saasURLHeader   = "SaaS URL"
+	orgKeyHeader    = "Org Key"
+	keyringHeader   = "Keyring"
+	storeHeader     = "Credential Store"
+	buildStepHeader = "Default Build Step"
+	optionHeader    = "Option"
+	valueHeader     = "Value"
+)
+
+// profileList is the list of user profiles to present.
+type profileList struct {
+	Profiles []config.Profile `json:"profiles"`
+}
+
+// Title is the title of the user profile list.
+func (p *profileList) Title() string {
+	return "User profiles:"
+}
+
+// Footer is empty for the user profile list.
+func (p *profileList) Footer() string {
+	return ""
+}
+
+// Header is the header columns of the user profile list.
+func (p *profileList) Header() []string {
+	return []string{activeHeader, nameHeader, saasURLHeader, orgKeyHeader, keyringHeader, storeHeader, buildStepHeader}
+}
+
+// Rows returns all the user profiles as list of rows.
+func (p *profileList) Rows() [][]string {
+	result := make([][]string, 0, len(p.Profiles))
+
+	for _, profile := range p.Profiles {
+		active := ""
+		if profile.Active {
+			active = activeMarker
+		}
+
+		result = append(result, []string{
+			active,
+			profile.Name,
+			profile.SaasURL,
+			profile.OrgKey,
+			strconv.FormatBool(profile.AuthByKeyring),
+			profile.CredentialStore,
+			profile.DefaultBuildStep,
+		})
+	}
+
+	return result
+}
+
+// profileDetail is a single user profile to present.
+type profileDetail struct {
+	config.Profile `json:",inline"`
+}
+
+// Title is the title of the user profile.
+func (p *profileDetail) Title() string {
+	return "User profile " + p.Name + ":"
+}
+
+// Footer is empty for the user profile.
+func (p *profileDetail) Footer() string {
+	return ""
+}
+
+// Header is the header columns of the user profile.
+func (p *profileDetail) Header() []string {
+	return []string{optionHeader, valueHeader}
+}
+
+// Rows returns the options of the user profile as list of rows.
+func (p *profileDetail) Rows() [][]string {
+	return [][]string{
+		{"active", strconv.FormatBool(p.Active)},
+		{config.SaasURL.String(), p.SaasURL},
+		{config.OrgKey.String(), p.OrgKey},
+		{config.CBApiID.String(), p.CBApiID},
+		{config.CBApiKey.String(), p.CBApiKey},
+		{"auth_by_keyring", strconv.FormatBool(p.AuthByKeyring)},
+		{"credential_store", p.CredentialStore},
+		{config.DefaultBuildStep.String(), p.DefaultBuildStep},
+	}
+}
@@ -0,0 +1,39 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+)
+
+const renameArgs = 2
+
+// RenameCmd will return the user rename sub command.
+func RenameCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <user> <new_name>",
+		Short: "Rename an existing user",
+		Long:  `Rename an existing user, the credentials stored in the keyring are moved to the new name.`,
+		Args:  cobra.ExactArgs(renameArgs),
+		Run: func(cmd *cobra.Command, args []string) {
+			go renameUser(args[0], args[1])
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+}
+
+func renameUser(user, newName string) {
+	user = config.ConvertToValidProfileName(user)
+	newName = config.ConvertToValidProfileName(newName)
+
+	if err := config.RenameProfile(user, newName); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	msg := fmt.Sprintf("User %s has been renamed to %s", user, newName)
+	bus.Publish(bus.NewMessageEvent(msg, true))
+}
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+)
+
+// ShowCmd will return the user show sub command.
+func ShowCmd() *cobra.Command {
+	var opts presenter.Option
+
+	showCmd := &cobra.Command{
+		Use:   "show [user]",
+		Short: "Show a user profile",
+		Long:  `Show a user profile, the active one by default, with the api key masked.`,
+		Args:  cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			user := config.GetConfig(config.ActiveUserProfile)
+			if len(args) > 0 {
+				user = config.ConvertToValidProfileName(args[0])
+			}
+
+			go showUser(user, opts)
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+
+	showCmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "table", "output format of the result")
+
+	return showCmd
+}
+
+func showUser(user string, opts presenter.Option) {
+	if opts.OutputFormat == "cyclonedx" || opts.OutputFormat == "c" {
+		e := cberr.NewError(cberr.ConfigErr, "The user show only supports table, json and markdown output", nil)
+		bus.Publish(bus.NewErrorEvent(e))
+
+		return
+	}
+
+	profile, err := config.GetProfile(user)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	bus.Publish(bus.NewEvent(bus.PrintProfiles, presenter.NewPresenter(&profileDetail{Profile: profile}, opts), true))
+}
@@ -0,0 +1,36 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui"
+)
+
+// UseCmd will return the user use sub command.
+func UseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use <user>",
+		Short: "Set the active user profile",
+		Long:  `Set an existing user profile as the active one.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			go useUser(args[0])
+			terminalui.NewDisplay().DisplayEvents()
+		},
+	}
+}
+
+func useUser(user string) {
+	user = config.ConvertToValidProfileName(user)
+
+	if err := config.UseProfile(user); err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return
+	}
+
+	msg := fmt.Sprintf("Active user profile selected: %s", user)
+	bus.Publish(bus.NewMessageEvent(msg, true))
+}
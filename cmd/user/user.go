@@ -16,6 +16,9 @@ func Cmd() *cobra.Command {
 	cmd.AddCommand(ListCmd())
 	cmd.AddCommand(AddCmd())
 	cmd.AddCommand(DelCmd())
+	cmd.AddCommand(UseCmd())
+	cmd.AddCommand(ShowCmd())
+	cmd.AddCommand(RenameCmd())
 
 	return cmd
 }
@@ -5,6 +5,7 @@ import (
 	stereoevent "github.com/anchore/stereoscope/pkg/event"
 	syftevent "github.com/anchore/syft/syft/event"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/logtool"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 )
 
@@ -21,9 +22,17 @@ const (
 	NewErrorDetected               EventType = "new-error-event"
 	NewCollectLayers               EventType = "new-collect-layers"
 	ScanStarted                    EventType = "image-scanning-started-event"
+	UploadStarted                  EventType = "upload-started-event"
 	ScanFinished                   EventType = "image-scanning-finished-event"
+	RemediationFinished            EventType = "remediation-finished-event"
+	DiffFinished                   EventType = "diff-finished-event"
 	PrintSBOM                      EventType = "print-sbom-event"
 	PrintPayload                   EventType = "print-payload-event"
+	PrintHistory                   EventType = "print-history-event"
+	PrintProfiles                  EventType = "print-profiles-event"
+	DoctorFinished                 EventType = "doctor-finished-event"
+	AttestationVerified            EventType = "attestation-verified-event"
+	PrintReferrers                 EventType = "print-referrers-event"
 	ValidateFinishedWithViolations EventType = "validate-finished-with-violations"
 	ValidateFinishedSuccessfully   EventType = "validate-finished-successfully"
 
@@ -62,7 +71,7 @@ func NewVersionEvent(version string) Event {
 
 // NewMessageEvent returns a NewMessageDetected type event.
 func NewMessageEvent(msg string, isEnd bool) Event {
-	return newBaseEvent(NewMessageDetected, msg, isEnd)
+	return newBaseEvent(NewMessageDetected, logtool.Redact(msg), isEnd)
 }
 
 // baseEvent wraps the type and value that define an Event.
@@ -103,7 +112,7 @@ type ErrorEvent struct {
 
 // NewErrorEvent returns a NewErrorDetected type event.
 func NewErrorEvent(err error) *ErrorEvent {
-	msg := fmt.Sprintf("Shutting down the CLI tool:\n%s\n", cberr.ErrorMessage(err))
+	msg := fmt.Sprintf("Shutting down the CLI tool:\n%s\n", logtool.Redact(cberr.ErrorMessage(err)))
 
 	return &ErrorEvent{
 		Event: newBaseEvent(NewErrorDetected, msg, true),
@@ -115,3 +124,8 @@ func NewErrorEvent(err error) *ErrorEvent {
 func (e ErrorEvent) ExitCode() int {
 	return cberr.ErrorExitCode(e.err)
 }
+
+// Err returns the error of the event.
+func (e ErrorEvent) Err() error {
+	return e.err
+}
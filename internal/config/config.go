@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/sirupsen/logrus"
@@ -18,10 +20,22 @@ var (
 	//
 	// It is a map of flag key to usage.
 	ConfigFileOverrides = map[string]string{
-		"cb-api-id":  "the API ID to be used for authorization",
-		"cb-api-key": "the API Key to be used for authorization",
-		"org-key":    "the Org key of the organization in CBC",
-		"saas-url":   "the Base URL of the CBC backend",
+		"cb-api-id":       "the API ID to be used for authorization",
+		"cb-api-key":      "the API Key to be used for authorization",
+		"cb-api-key-file": "the file holding the API Key to be used for authorization",
+		"org-key":         "the Org key of the organization in CBC",
+		"saas-url":        "the Base URL of the CBC backend",
+
+		"retry-max-attempts": "the max count of attempts for a request to the backend",
+		"retry-max-delay":    "the max delay in seconds between two attempts of a request",
+
+		"proxy-url":   "the proxy for the connections to the CBC backend",
+		"no-proxy":    "the comma-separated hosts connected without the proxy",
+		"ca-bundle":   "the PEM file of the extra CA certificates trusted for the CBC backend",
+		"client-cert": "the PEM file of the client certificate for mutual TLS",
+		"client-key":  "the PEM file of the client key for mutual TLS",
+
+		"log-redact-patterns": "the space-separated regular expressions of the secrets redacted from the logs",
 	}
 )
 
@@ -34,10 +48,22 @@ const (
 
 // AppConfig is the config of the cli app.
 type AppConfig struct {
+	// ConfigHome is the directory holding the default config file and other local state of the cli
+	ConfigHome        string
 	ActiveUserProfile string
 	AccessToKeyring   bool
 	Properties        map[string]*Property
 	CliOpt            *CliOption
+
+	// ProjectConfigFile is the project config file found from the working directory, if any
+	ProjectConfigFile string
+
+	// readOnly is set if the config file can not be read or has a newer schema version,
+	// so that it is not overwritten with a partial config
+	readOnly bool
+	// layers are the values of the active profile from the project config file, env variables and flags,
+	// they are never persisted
+	layers map[Option]layeredValue
 }
 
 // Property is the property of a single user.
@@ -48,6 +74,36 @@ type Property struct {
 	CBApiID          string
 	CBApiKey         string
 	DefaultBuildStep string
+	// HistoryMaxEntries and HistoryMaxAgeDays are the retention of the local scan history
+	HistoryMaxEntries string
+	HistoryMaxAgeDays string
+	// RetryMaxAttempts and RetryMaxDelay are the retry policy of the requests to the backend
+	RetryMaxAttempts string
+	RetryMaxDelay    string
+	// ProxyURL, NoProxy, CABundle, ClientCert and ClientKey are the transport settings of the backend connections
+	ProxyURL   string
+	NoProxy    string
+	CABundle   string
+	ClientCert string
+	ClientKey  string
+	// Namespace, FailOn and BaselineFile are the defaults of the image commands
+	Namespace    string
+	FailOn       string
+	BaselineFile string
+	// CBApiKeyFile and CredentialProcess are the external sources of the credentials
+	CBApiKeyFile      string
+	CredentialProcess string
+	// LogRedactPatterns are the extra patterns of the secrets redacted from the logs
+	LogRedactPatterns string
+	// CatalogScope, Catalogers, CatalogExclusions and SearchArchives are the defaults of the cataloging
+	CatalogScope      string
+	Catalogers        string
+	CatalogExclusions string
+	SearchArchives    string
+	// AuthByFile is set if the api id and key are in the encrypted credential file instead of the keyring
+	AuthByFile bool
+	// secretsLoaded is set once the api id and key are read from the encrypted file, or set
+	secretsLoaded bool
 }
 
 // CliOption contains all the cli flag options.
@@ -55,6 +111,8 @@ type CliOption struct {
 	ConfigFile  string `mapstructure:"config"`
 	UserProfile string `mapstructure:"user-profile"`
 	PlainMode   bool   `mapstructure:"plain-mode"`
+	UI          string `mapstructure:"ui"`
+	APIKeyStdin bool   `mapstructure:"cb-api-key-stdin"`
 }
 
 func init() {
@@ -63,6 +121,7 @@ func init() {
 		AccessToKeyring:   false,
 		Properties:        make(map[string]*Property),
 		CliOpt:            &CliOption{},
+		layers:            make(map[Option]layeredValue),
 	}
 }
 
@@ -71,8 +130,11 @@ func Config() *AppConfig {
 	return appConfig
 }
 
-// LoadAppConfig will initialize application config from viper.
-func LoadAppConfig() {
+// LoadAppConfig will initialize application config from viper,
+// the returned error lists the problems of the config file, which is loaded anyway.
+func LoadAppConfig() error {
+	var fileErr error
+
 	if err := viper.Unmarshal(appConfig.CliOpt); err != nil {
 		logrus.Println("Cannot bind cli flag options: ", err)
 	}
@@ -92,6 +154,7 @@ func LoadAppConfig() {
 	// if a config file is found, read it in
 	if err := viper.ReadInConfig(); err == nil {
 		logrus.Println("Using config file: ", viper.ConfigFileUsed())
+		fileErr = loadConfigFile(viper.ConfigFileUsed())
 	} else {
 		logrus.Println("No config file detected")
 	}
@@ -104,31 +167,104 @@ func LoadAppConfig() {
 		appConfig.AccessToKeyring = true
 	}
 
+	fileStore = NewEncryptedStore(filepath.Join(appConfig.ConfigHome, CredentialFileName))
+
 	setUserProfiles()
+
+	return fileErr
+}
+
+// loadConfigFile will migrate the settings of the config file to the current schema version and validate them.
+func loadConfigFile(path string) error {
+	settings, err := ReadConfigFile(path)
+	if err != nil {
+		// do not overwrite a config file which can not be understood
+		appConfig.readOnly = true
+		return err
+	}
+
+	if err := viper.MergeConfigMap(settings); err != nil {
+		return cberr.NewError(cberr.ConfigErr, "Failed to migrate config file", err)
+	}
+
+	return ValidateSettings(settings)
 }
 
 // GetConfig will get the config by a given option from the active profile.
 func GetConfig(o Option) string {
-	switch o {
-	case ActiveUserProfile:
+	if o == ActiveUserProfile {
 		return appConfig.ActiveUserProfile
+	}
+
+	// the flags, env variables and project config file take precedence over the profile
+	if layered, ok := appConfig.layers[o]; ok {
+		return layered.value
+	}
+
+	if o == CBApiID || o == CBApiKey {
+		value, _ := resolveCredential(o)
+		return value
+	}
+
+	return appConfig.Properties[appConfig.ActiveUserProfile].get(o)
+}
+
+// get will return the value of the option of the property, the active profile is not an option of a property.
+func (p *Property) get(o Option) string {
+	switch o {
 	case SaasURL:
-		return appConfig.Properties[appConfig.ActiveUserProfile].SaasURL
+		return p.SaasURL
 	case OrgKey:
-		return appConfig.Properties[appConfig.ActiveUserProfile].OrgKey
+		return p.OrgKey
 	case CBApiID:
-		return appConfig.Properties[appConfig.ActiveUserProfile].CBApiID
+		return p.CBApiID
 	case CBApiKey:
-		return appConfig.Properties[appConfig.ActiveUserProfile].CBApiKey
+		return p.CBApiKey
 	case DefaultBuildStep:
-		return appConfig.Properties[appConfig.ActiveUserProfile].DefaultBuildStep
-	case cntOfOptions:
+		return p.DefaultBuildStep
+	case HistoryMaxEntries:
+		return p.HistoryMaxEntries
+	case HistoryMaxAgeDays:
+		return p.HistoryMaxAgeDays
+	case RetryMaxAttempts:
+		return p.RetryMaxAttempts
+	case RetryMaxDelay:
+		return p.RetryMaxDelay
+	case ProxyURL:
+		return p.ProxyURL
+	case NoProxy:
+		return p.NoProxy
+	case CABundle:
+		return p.CABundle
+	case ClientCert:
+		return p.ClientCert
+	case ClientKey:
+		return p.ClientKey
+	case Namespace:
+		return p.Namespace
+	case FailOn:
+		return p.FailOn
+	case BaselineFile:
+		return p.BaselineFile
+	case CBApiKeyFile:
+		return p.CBApiKeyFile
+	case CredentialProcess:
+		return p.CredentialProcess
+	case LogRedactPatterns:
+		return p.LogRedactPatterns
+	case CatalogScope:
+		return p.CatalogScope
+	case Catalogers:
+		return p.Catalogers
+	case CatalogExclusions:
+		return p.CatalogExclusions
+	case SearchArchives:
+		return p.SearchArchives
+	case ActiveUserProfile, cntOfOptions:
 		fallthrough
 	default:
 		panic(fmt.Sprintf("Invalid config option provided: %v", o))
 	}
-
-	return ""
 }
 
 // SetConfigByOption will set the config by a given option-value to the active profile.
@@ -146,10 +282,50 @@ func SetConfigByOption(user string, o Option, value string) {
 		appConfig.Properties[user].OrgKey = value
 	case CBApiKey:
 		appConfig.Properties[user].CBApiKey = value
+		appConfig.Properties[user].secretsLoaded = true
 	case CBApiID:
 		appConfig.Properties[user].CBApiID = value
+		appConfig.Properties[user].secretsLoaded = true
 	case DefaultBuildStep:
 		appConfig.Properties[user].DefaultBuildStep = value
+	case HistoryMaxEntries:
+		appConfig.Properties[user].HistoryMaxEntries = value
+	case HistoryMaxAgeDays:
+		appConfig.Properties[user].HistoryMaxAgeDays = value
+	case RetryMaxAttempts:
+		appConfig.Properties[user].RetryMaxAttempts = value
+	case RetryMaxDelay:
+		appConfig.Properties[user].RetryMaxDelay = value
+	case ProxyURL:
+		appConfig.Properties[user].ProxyURL = value
+	case NoProxy:
+		appConfig.Properties[user].NoProxy = value
+	case CABundle:
+		appConfig.Properties[user].CABundle = value
+	case ClientCert:
+		appConfig.Properties[user].ClientCert = value
+	case ClientKey:
+		appConfig.Properties[user].ClientKey = value
+	case Namespace:
+		appConfig.Properties[user].Namespace = value
+	case FailOn:
+		appConfig.Properties[user].FailOn = value
+	case BaselineFile:
+		appConfig.Properties[user].BaselineFile = value
+	case CBApiKeyFile:
+		appConfig.Properties[user].CBApiKeyFile = value
+	case CredentialProcess:
+		appConfig.Properties[user].CredentialProcess = value
+	case LogRedactPatterns:
+		appConfig.Properties[user].LogRedactPatterns = value
+	case CatalogScope:
+		appConfig.Properties[user].CatalogScope = value
+	case Catalogers:
+		appConfig.Properties[user].Catalogers = value
+	case CatalogExclusions:
+		appConfig.Properties[user].CatalogExclusions = value
+	case SearchArchives:
+		appConfig.Properties[user].SearchArchives = value
 	case ActiveUserProfile, cntOfOptions:
 		fallthrough
 	default:
@@ -159,19 +335,49 @@ func SetConfigByOption(user string, o Option, value string) {
 
 // PersistConfig will persist all the configs.
 func PersistConfig() error {
+	if appConfig.readOnly {
+		logrus.Warnf("Config file %s can not be understood, skip persisting config", appConfig.CliOpt.ConfigFile)
+		return nil
+	}
+
 	writeViper := viper.New()
 
+	writeViper.Set(versionKey, SchemaVersion)
 	writeViper.Set(ActiveUserProfile.String(), appConfig.ActiveUserProfile)
 
 	for user, profile := range appConfig.Properties {
 		writeViper.Set(SaasURL.StringWithPrefix(user), profile.SaasURL)
 		writeViper.Set(OrgKey.StringWithPrefix(user), profile.OrgKey)
 		writeViper.Set(DefaultBuildStep.StringWithPrefix(user), profile.DefaultBuildStep)
+		writeViper.Set(HistoryMaxEntries.StringWithPrefix(user), profile.HistoryMaxEntries)
+		writeViper.Set(HistoryMaxAgeDays.StringWithPrefix(user), profile.HistoryMaxAgeDays)
+		writeViper.Set(RetryMaxAttempts.StringWithPrefix(user), profile.RetryMaxAttempts)
+		writeViper.Set(RetryMaxDelay.StringWithPrefix(user), profile.RetryMaxDelay)
+		writeViper.Set(ProxyURL.StringWithPrefix(user), profile.ProxyURL)
+		writeViper.Set(NoProxy.StringWithPrefix(user), profile.NoProxy)
+		writeViper.Set(CABundle.StringWithPrefix(user), profile.CABundle)
+		writeViper.Set(ClientCert.StringWithPrefix(user), profile.ClientCert)
+		writeViper.Set(ClientKey.StringWithPrefix(user), profile.ClientKey)
+		writeViper.Set(Namespace.StringWithPrefix(user), profile.Namespace)
+		writeViper.Set(FailOn.StringWithPrefix(user), profile.FailOn)
+		writeViper.Set(BaselineFile.StringWithPrefix(user), profile.BaselineFile)
+		writeViper.Set(CBApiKeyFile.StringWithPrefix(user), profile.CBApiKeyFile)
+		writeViper.Set(CredentialProcess.StringWithPrefix(user), profile.CredentialProcess)
+		writeViper.Set(LogRedactPatterns.StringWithPrefix(user), profile.LogRedactPatterns)
+		writeViper.Set(CatalogScope.StringWithPrefix(user), profile.CatalogScope)
+		writeViper.Set(Catalogers.StringWithPrefix(user), profile.Catalogers)
+		writeViper.Set(CatalogExclusions.StringWithPrefix(user), profile.CatalogExclusions)
+		writeViper.Set(SearchArchives.StringWithPrefix(user), profile.SearchArchives)
+
+		// overwrite with mask value for those values saved in keyring or in the encrypted file
+		if profile.StoresCredentials() {
+			mask := authenticatedByKeyringMaskValue
+			if profile.AuthByFile {
+				mask = encryptedFileMaskValue
+			}
 
-		// overwrite with mask value for those values saved in keyring
-		if profile.AuthByKeyring {
-			writeViper.Set(CBApiKey.StringWithPrefix(user), authenticatedByKeyringMaskValue)
-			writeViper.Set(CBApiID.StringWithPrefix(user), authenticatedByKeyringMaskValue)
+			writeViper.Set(CBApiKey.StringWithPrefix(user), mask)
+			writeViper.Set(CBApiID.StringWithPrefix(user), mask)
 		} else {
 			writeViper.Set(CBApiKey.StringWithPrefix(user), profile.CBApiKey)
 			writeViper.Set(CBApiID.StringWithPrefix(user), profile.CBApiID)
@@ -227,6 +433,14 @@ func setUserProfiles() {
 		}
 
 		value := viper.GetString(key)
+		// the values in the encrypted file are only read on demand, since its passphrase may be prompted
+		if value == encryptedFileMaskValue {
+			initUserProperty(user)
+			appConfig.Properties[user].AuthByFile = true
+
+			continue
+		}
+
 		// if the value is authenticated means we are fetching result from keyring,
 		// do not overwrite it with the mask value
 		if value == authenticatedByKeyringMaskValue {
@@ -243,6 +457,10 @@ func setUserProfiles() {
 		}
 	}
 
+	// the project config file overrides the config file, for the active profile only
+	appConfig.layers = make(map[Option]layeredValue)
+	loadProjectConfig()
+
 	// go over the options which we allow to be overridden via CLI args
 	// if any of them is present use it instead of the one in the config file
 	for key := range ConfigFileOverrides {
@@ -254,9 +472,19 @@ func setUserProfiles() {
 
 		optionName := strings.ReplaceAll(key, cliFlagDelimeter, configValueDelimeter)
 		if _, option := Contains(optionName); option >= 0 {
-			SetConfigByOption(appConfig.ActiveUserProfile, option, value)
+			appConfig.layers[option] = overrideValue(key, value)
 		}
 	}
+
+	if appConfig.CliOpt.APIKeyStdin {
+		apiKey, err := readSecret(os.Stdin)
+		if err != nil {
+			logrus.Errorln(cberr.NewError(cberr.ConfigErr, "Failed to read the api key from stdin", err))
+			return
+		}
+
+		appConfig.layers[CBApiKey] = layeredValue{value: apiKey, source: SourceFlag, origin: "--cb-api-key-stdin"}
+	}
 }
 
 func initUserProperty(name string) {
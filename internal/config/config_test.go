@@ -8,6 +8,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
@@ -250,3 +251,422 @@ func TestConvertToValidProfileName(t *testing.T) {
 		t.Errorf("Profile name converted wrongly: %s", convertedProfile)
 	}
 }
+
+func TestManageProfiles(t *testing.T) {
+	viper.Set("user-profile", "profile-a")
+	viper.Set("cb_api_key", "[authenticated by keyring]")
+	viper.Set("cb_api_id", "[authenticated by keyring]")
+	viper.Set("profile-b.org_key", "org-b")
+
+	defer viper.Reset()
+
+	keyring.MockInit()
+	_ = keyring.Set("cbctl_profile-a", config.CBApiKey.String(), "secret-api-key")
+	_ = keyring.Set("cbctl_profile-a", config.CBApiID.String(), "api-id")
+	config.LoadAppConfig()
+
+	profile, err := config.GetProfile("cbctl_profile-a")
+	require.NoError(t, err)
+	require.True(t, profile.Active)
+	require.True(t, profile.AuthByKeyring)
+	require.Equal(t, "****-key", profile.CBApiKey)
+
+	_, err = config.GetProfile("cbctl_missing")
+	require.Error(t, err)
+
+	require.NoError(t, config.UseProfile("profile-b"))
+	require.Equal(t, "profile-b", config.GetConfig(config.ActiveUserProfile))
+	require.Error(t, config.UseProfile("cbctl_missing"))
+
+	// the credentials in the keyring follow the renamed profile
+	require.NoError(t, config.RenameProfile("cbctl_profile-a", "cbctl_profile-c"))
+	require.Error(t, config.RenameProfile("cbctl_profile-c", "profile-b"))
+
+	apiKey, err := keyring.Get("cbctl_profile-c", config.CBApiKey.String())
+	require.NoError(t, err)
+	require.Equal(t, "secret-api-key", apiKey)
+
+	_, err = keyring.Get("cbctl_profile-a", config.CBApiKey.String())
+	require.Equal(t, keyring.ErrNotFound, err)
+
+	require.NoError(t, config.DeleteProfile("profile-b"))
+	require.Equal(t, "cbctl_default", config.GetConfig(config.ActiveUserProfile))
+
+	names := make([]string, 0)
+	for _, p := range config.Profiles() {
+		names = append(names, p.Name)
+	}
+
+	require.Contains(t, names, "cbctl_profile-c")
+	require.NotContains(t, names, "profile-b")
+	require.NotContains(t, names, "cbctl_profile-a")
+}
+
+func TestReadConfigFileMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	legacy := filepath.Join(dir, "legacy.yaml")
+	legacyContent := "active_user_profile: team\norg_key: ORG\nsaas_url: https://legacy.com\ncbctl_team:\n  org_key: TEAM\n"
+	require.NoError(t, os.WriteFile(legacy, []byte(legacyContent), 0600))
+
+	settings, err := config.ReadConfigFile(legacy)
+	require.NoError(t, err)
+	require.Equal(t, config.SchemaVersion, settings["version"])
+	require.NotContains(t, settings, "org_key")
+
+	// the top level options are moved to the active profile, without overriding its own
+	profile := settings["cbctl_team"].(map[string]interface{})
+	require.Equal(t, "TEAM", profile["org_key"])
+	require.Equal(t, "https://legacy.com", profile["saas_url"])
+	require.NoError(t, config.ValidateSettings(settings))
+
+	newer := filepath.Join(dir, "newer.yaml")
+	require.NoError(t, os.WriteFile(newer, []byte("version: 99\n"), 0600))
+
+	_, err = config.ReadConfigFile(newer)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "newer than the supported version")
+}
+
+func TestValidateSettings(t *testing.T) {
+	settings := map[string]interface{}{
+		"version":   config.SchemaVersion,
+		"unknown":   "value",
+		"cbctl_dev": map[string]interface{}{"saas_url": "ftp//bad", "orgkey": "ORG"},
+		"cbctl_ok":  map[string]interface{}{"saas_url": "https://ok.com", "org_key": "ORG"},
+	}
+
+	err := config.ValidateSettings(settings)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown key "unknown"`)
+	require.Contains(t, err.Error(), `malformed saas_url "ftp//bad" in profile cbctl_dev`)
+	require.Contains(t, err.Error(), "missing org_key in profile cbctl_dev")
+	require.Contains(t, err.Error(), "did you mean org_key?")
+	require.NotContains(t, err.Error(), "cbctl_ok")
+}
+
+func TestExportImportProfiles(t *testing.T) {
+	viper.Set("user-profile", "exporter")
+	viper.Set("org_key", "ORG")
+	viper.Set("saas_url", "https://export.com")
+	viper.Set("cb_api_id", "api-id")
+	viper.Set("cb_api_key", "api-key")
+
+	defer viper.Reset()
+
+	keyring.MockInit()
+	config.LoadAppConfig()
+
+	settings, err := config.ExportProfiles([]string{"cbctl_exporter"}, false)
+	require.NoError(t, err)
+
+	profile := settings["cbctl_exporter"].(map[string]interface{})
+	require.Equal(t, "ORG", profile["org_key"])
+	require.NotContains(t, profile, "cb_api_key")
+
+	_, err = config.ExportProfiles([]string{"cbctl_missing"}, false)
+	require.Error(t, err)
+
+	settings, err = config.ExportProfiles([]string{"cbctl_exporter"}, true)
+	require.NoError(t, err)
+	require.Equal(t, "api-key", settings["cbctl_exporter"].(map[string]interface{})["cb_api_key"])
+
+	// the existing profiles are only replaced with overwrite
+	_, err = config.ImportProfiles(settings, false)
+	require.Error(t, err)
+
+	settings["importer"] = settings["cbctl_exporter"]
+	delete(settings, "cbctl_exporter")
+
+	names, err := config.ImportProfiles(settings, false)
+	require.NoError(t, err)
+	require.Equal(t, []string{"cbctl_importer"}, names)
+
+	// the imported credentials are moved to the keyring
+	require.True(t, config.Config().Properties["cbctl_importer"].AuthByKeyring)
+
+	apiKey, err := keyring.Get("cbctl_importer", config.CBApiKey.String())
+	require.NoError(t, err)
+	require.Equal(t, "api-key", apiKey)
+}
+
+func TestProjectConfigLayers(t *testing.T) {
+	root := t.TempDir()
+	workDir := filepath.Join(root, "service", "src")
+	require.NoError(t, os.MkdirAll(workDir, 0700))
+
+	projectContent := "version: 1\norg_key: PROJECT\nfail_on: high\nbaseline_file: baseline.json\ncb_api_key: leaked\n" +
+		"proxy_url: http://proxy.attacker.com\n"
+	projectFile := filepath.Join(root, config.ProjectConfigName)
+	require.NoError(t, os.WriteFile(projectFile, []byte(projectContent), 0600))
+	require.Equal(t, projectFile, config.FindProjectConfig(workDir, ""))
+	require.Empty(t, config.FindProjectConfig(workDir, projectFile))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(workDir))
+
+	defer func() { _ = os.Chdir(wd) }()
+
+	viper.Set("user-profile", "layers")
+	viper.Set("org_key", "USER")
+	viper.Set("saas_url", "https://user.com")
+	viper.Set("cb_api_key", "user-api-key-1234")
+	viper.Set("saas-url", "https://flag.com")
+
+	defer viper.Reset()
+
+	config.LoadAppConfig()
+
+	// flags > project > user, only the build and scan options are read from the project
+	require.Equal(t, "https://flag.com", config.GetConfig(config.SaasURL))
+	require.Equal(t, "USER", config.GetConfig(config.OrgKey))
+	require.Empty(t, config.GetConfig(config.ProxyURL))
+	require.Equal(t, "high", config.GetConfig(config.FailOn))
+	require.Equal(t, filepath.Join(root, "baseline.json"), config.GetConfig(config.BaselineFile))
+	require.Equal(t, "user-api-key-1234", config.GetConfig(config.CBApiKey))
+	require.Equal(t, projectFile, config.Config().ProjectConfigFile)
+
+	// the layered values are not saved in the profile
+	require.Equal(t, "USER", config.Config().Properties["cbctl_layers"].OrgKey)
+
+	sources := make(map[string]config.EffectiveOption)
+	for _, option := range config.EffectiveConfig() {
+		sources[option.Option] = option
+	}
+
+	require.Equal(t, config.SourceFlag, sources["saas_url"].Source)
+	require.Equal(t, config.SourceUser, sources["org_key"].Source)
+	require.Equal(t, config.SourceProject, sources["fail_on"].Source)
+	require.Equal(t, projectFile, sources["fail_on"].Origin)
+	require.Equal(t, config.SourceUser, sources["cb_api_key"].Source)
+	require.Equal(t, "****1234", sources["cb_api_key"].Value)
+	require.Equal(t, config.SourceDefault, sources["namespace"].Source)
+
+	for _, option := range config.SavedConfig() {
+		if option.Option == "org_key" {
+			require.Equal(t, "USER", option.Value)
+			require.Equal(t, config.SourceUser, option.Source)
+		}
+	}
+}
+
+func TestProjectConfigRejected(t *testing.T) {
+	projectContent := "version: 1\nsaas_url: https://attacker.com\nproxy_url: http://proxy.attacker.com\n" +
+		"ca_bundle: ca.pem\ncredential_process: steal\nnamespace: team\nbad_key: 1\n"
+	projectFile := filepath.Join(t.TempDir(), config.ProjectConfigName)
+	require.NoError(t, os.WriteFile(projectFile, []byte(projectContent), 0600))
+
+	// the valid build and scan options are still read
+	options, err := config.ReadProjectConfig(projectFile)
+	require.Error(t, err)
+	require.Equal(t, map[config.Option]string{config.Namespace: "team"}, options)
+
+	for _, key := range []string{"saas_url", "proxy_url", "ca_bundle", "credential_process"} {
+		require.Contains(t, err.Error(), key+" is ignored, it is only read from the user config")
+	}
+
+	require.Contains(t, err.Error(), `unknown key "bad_key"`)
+}
+
+func TestExternalCredentials(t *testing.T) {
+	dir := t.TempDir()
+
+	keyFile := filepath.Join(dir, "api-key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("key-from-file\n"), 0600))
+
+	// the script counts its runs, to check the credentials are cached until their expiry
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	script := filepath.Join(dir, "credentials.sh")
+	scriptContent := fmt.Sprintf("echo run >> %s/runs\necho '{\"api_id\": \"process-id\", \"api_key\": \"process-key\", "+
+		"\"expires_at\": \"%s\"}'\n", dir, expiresAt)
+	require.NoError(t, os.WriteFile(script, []byte(scriptContent), 0600))
+
+	viper.Set("user-profile", "external")
+	viper.Set("cb_api_id", "saved-id")
+	viper.Set("cb_api_key", "saved-key")
+	viper.Set("credential_process", "sh "+script)
+
+	defer viper.Reset()
+
+	config.Config().ConfigHome = dir
+
+	defer func() { config.Config().ConfigHome = "" }()
+
+	config.LoadAppConfig()
+
+	require.Equal(t, "process-id", config.GetConfig(config.CBApiID))
+	require.Equal(t, "process-key", config.GetConfig(config.CBApiKey))
+
+	// the key file takes precedence over the credential process
+	viper.Set("cb-api-key-file", keyFile)
+	config.LoadAppConfig()
+
+	require.Equal(t, "process-id", config.GetConfig(config.CBApiID))
+	require.Equal(t, "key-from-file", config.GetConfig(config.CBApiKey))
+
+	runs, err := os.ReadFile(filepath.Join(dir, "runs"))
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(runs), "run"))
+
+	cached, err := filepath.Glob(filepath.Join(dir, "credential_cache", "*.json"))
+	require.NoError(t, err)
+	require.Len(t, cached, 1)
+
+	_, err = config.RunCredentialProcess("sh " + filepath.Join(dir, "missing.sh"))
+	require.Error(t, err)
+}
+
+func TestFailedCredentialProcess(t *testing.T) {
+	dir := t.TempDir()
+
+	// the script counts its runs, to check a failure is not run again
+	script := filepath.Join(dir, "failing.sh")
+	scriptContent := fmt.Sprintf("echo run >> %s/runs\nexit 1\n", dir)
+	require.NoError(t, os.WriteFile(script, []byte(scriptContent), 0600))
+
+	viper.Set("user-profile", "failing")
+	viper.Set("cb_api_id", "saved-id")
+	viper.Set("cb_api_key", "saved-key")
+	viper.Set("credential_process", "sh "+script)
+
+	defer viper.Reset()
+
+	config.Config().ConfigHome = dir
+
+	defer func() { config.Config().ConfigHome = "" }()
+
+	config.LoadAppConfig()
+
+	// the saved credentials are not used instead of the failed process
+	require.Equal(t, "", config.GetConfig(config.CBApiID))
+	require.Equal(t, "", config.GetConfig(config.CBApiKey))
+	require.Error(t, config.CredentialError())
+
+	runs, err := os.ReadFile(filepath.Join(dir, "runs"))
+	require.NoError(t, err)
+	require.Equal(t, 1, strings.Count(string(runs), "run"))
+}
+
+func TestUnreadableKeyFile(t *testing.T) {
+	viper.Set("user-profile", "unreadable")
+	viper.Set("cb_api_key", "saved-key")
+	viper.Set("cb-api-key-file", filepath.Join(t.TempDir(), "missing"))
+
+	defer viper.Reset()
+
+	config.LoadAppConfig()
+
+	// the saved key is not used instead of the key file
+	require.Equal(t, "", config.GetConfig(config.CBApiKey))
+	require.Error(t, config.CredentialError())
+}
+
+func TestEncryptedCredentialStore(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+
+	t.Setenv(config.PassphraseEnv, "correct horse")
+
+	viper.Set("user-profile", "headless")
+	viper.Set("cb_api_id", "plain-id")
+	viper.Set("cb_api_key", "plain-key")
+
+	config.Config().ConfigHome = dir
+
+	defer func() { config.Config().ConfigHome = "" }()
+
+	config.LoadAppConfig()
+
+	store, err := config.StoreCredentials("cbctl_headless", "plain-id", "plain-key", config.StoreEncryptedFile)
+	require.NoError(t, err)
+	require.Equal(t, config.StoreEncryptedFile, store)
+
+	credentialFile := filepath.Join(dir, config.CredentialFileName)
+	stat, err := os.Stat(credentialFile)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	content, err := os.ReadFile(credentialFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(content), "plain-key")
+
+	// the config file only keeps a mask value
+	config.Config().CliOpt.ConfigFile = configFile
+	require.NoError(t, config.PersistConfig())
+
+	saved, err := os.ReadFile(configFile)
+	require.NoError(t, err)
+	require.NotContains(t, string(saved), "plain-key")
+
+	viper.Reset()
+	viper.SetConfigFile(configFile)
+	viper.Set("user-profile", "headless")
+
+	defer viper.Reset()
+
+	config.LoadAppConfig()
+
+	require.Equal(t, "plain-id", config.GetConfig(config.CBApiID))
+	require.Equal(t, "plain-key", config.GetConfig(config.CBApiKey))
+	require.True(t, config.Config().Properties["cbctl_headless"].AuthByFile)
+
+	// the credentials of the file are not reported in the keyring
+	profile, err := config.GetProfile("cbctl_headless")
+	require.NoError(t, err)
+	require.False(t, profile.AuthByKeyring)
+	require.Equal(t, config.StoreEncryptedFile, profile.CredentialStore)
+
+	// the file can not be opened with another passphrase
+	t.Setenv(config.PassphraseEnv, "wrong")
+
+	_, err = config.NewEncryptedStore(credentialFile).Get("cbctl_headless")
+	require.Error(t, err)
+}
+
+func TestEncryptedStorePassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), config.CredentialFileName)
+	secrets := map[string]string{"cb_api_key": "file-key"}
+
+	t.Setenv(config.PassphraseEnv, "")
+
+	defer func() { config.PassphrasePrompt = nil }()
+
+	// without a prompt, the passphrase must be in the env
+	config.PassphrasePrompt = nil
+	err := config.NewEncryptedStore(path).Set("cbctl_default", secrets)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), config.PassphraseEnv)
+
+	// the passphrase of a new file is confirmed
+	var prompts []bool
+
+	answers := []string{"first", "second", "first", "first"}
+	config.PassphrasePrompt = func(confirm bool) (string, error) {
+		prompts = append(prompts, confirm)
+		answer := answers[0]
+		answers = answers[1:]
+
+		return answer, nil
+	}
+
+	require.Error(t, config.NewEncryptedStore(path).Set("cbctl_default", secrets))
+	require.NoError(t, config.NewEncryptedStore(path).Set("cbctl_default", secrets))
+	require.Equal(t, []bool{false, true, false, true}, prompts)
+
+	// a wrong passphrase is asked once
+	prompts = nil
+	answers = []string{"wrong"}
+	store := config.NewEncryptedStore(path)
+
+	_, err = store.Get("cbctl_default")
+	require.Error(t, err)
+
+	_, err = store.Get("cbctl_default")
+	require.Error(t, err)
+	require.Equal(t, []bool{false}, prompts)
+
+	// a missing file needs no passphrase
+	config.PassphrasePrompt = nil
+	require.NoError(t, config.NewEncryptedStore(path+".missing").Delete("cbctl_default"))
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+const (
+	credentialProcessTimeout = time.Minute
+	// credentialExpiryWindow is how long before their expiry the cached credentials are refreshed
+	credentialExpiryWindow = time.Minute
+	credentialCacheDir     = "credential_cache"
+	credentialCacheMode    = 0600
+	credentialCacheDirMode = 0700
+)
+
+// ProcessCredentials is the output of the credential process.
+type ProcessCredentials struct {
+	APIID  string `json:"api_id"`
+	APIKey string `json:"api_key"`
+	// ExpiresAt is when the credentials expire, they are cached until then; never cached if not set
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// valid returns true if the credentials are not about to expire.
+func (c *ProcessCredentials) valid(now time.Time) bool {
+	return c.ExpiresAt == nil || now.Add(credentialExpiryWindow).Before(*c.ExpiresAt)
+}
+
+// processCredentials are the credentials of the credential processes run, by command.
+var processCredentials = make(map[string]*ProcessCredentials)
+
+// processErrors are the failures of the credential processes run, by command, they are not run again by the process.
+var processErrors = make(map[string]error)
+
+// resolveCredential returns the api id or key of the active profile and where it comes from,
+// the key file and the credential process take precedence over the values saved in the profile.
+// The credentials are empty if the key file can not be read or the credential process fails, see CredentialError.
+func resolveCredential(o Option) (string, string) {
+	if o == CBApiKey {
+		if path := GetConfig(CBApiKeyFile); path != "" {
+			apiKey, err := ReadSecretFile(path)
+			if err != nil {
+				return "", path
+			}
+
+			return apiKey, path
+		}
+	}
+
+	if command := GetConfig(CredentialProcess); command != "" {
+		credentials, err := RunCredentialProcess(command)
+		if err != nil {
+			return "", CredentialProcess.String()
+		} else if o == CBApiID && credentials.APIID != "" {
+			return credentials.APIID, CredentialProcess.String()
+		} else if o == CBApiKey && credentials.APIKey != "" {
+			return credentials.APIKey, CredentialProcess.String()
+		}
+	}
+
+	if err := loadStoredSecrets(appConfig.ActiveUserProfile); err != nil {
+		logrus.Errorln(err)
+	}
+
+	property := appConfig.Properties[appConfig.ActiveUserProfile]
+	if property.StoresCredentials() {
+		return property.get(o), storeName(previousStore(property))
+	}
+
+	return property.get(o), ""
+}
+
+// CredentialError returns the failure to read the key file or to run the credential process of the active profile,
+// if any; the api key, or the api id and key, are then empty rather than the ones saved in the profile.
+func CredentialError() error {
+	if _, ok := appConfig.layers[CBApiKey]; ok {
+		return nil
+	}
+
+	if path := GetConfig(CBApiKeyFile); path != "" {
+		if _, err := ReadSecretFile(path); err != nil {
+			return err
+		}
+	}
+
+	command := GetConfig(CredentialProcess)
+	if command == "" {
+		return nil
+	}
+
+	_, err := RunCredentialProcess(command)
+
+	return err
+}
+
+// ReadSecretFile will read a secret from the first line of a file, which should only be readable by its owner.
+func ReadSecretFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to read secret file %s", path)
+		return "", cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	if stat, err := file.Stat(); err == nil && stat.Mode().Perm()&0077 != 0 {
+		logrus.Warnf("Secret file %s is accessible by other users, consider restricting its mode to 0600", path)
+	}
+
+	secret, err := readSecret(file)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to read secret file %s", path)
+		return "", cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return secret, nil
+}
+
+// readSecret will read a secret from the first line of r.
+func readSecret(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	secret := strings.TrimSpace(line)
+	if secret == "" {
+		return "", fmt.Errorf("no secret found")
+	}
+
+	return secret, nil
+}
+
+// RunCredentialProcess will return the credentials printed by the command as json,
+// they are cached until their expiry, also in the config home to be shared by the next runs.
+func RunCredentialProcess(command string) (*ProcessCredentials, error) {
+	now := time.Now()
+
+	if credentials, ok := processCredentials[command]; ok && credentials.valid(now) {
+		return credentials, nil
+	}
+
+	if err, ok := processErrors[command]; ok {
+		return nil, err
+	}
+
+	cacheFile := credentialCacheFile(command)
+	if credentials := readCachedCredentials(cacheFile); credentials != nil && credentials.valid(now) {
+		processCredentials[command] = credentials
+		return credentials, nil
+	}
+
+	credentials, err := runCredentialProcess(command)
+	if err != nil {
+		logrus.Errorln(err)
+		processErrors[command] = err
+
+		return nil, err
+	}
+
+	processCredentials[command] = credentials
+
+	if credentials.ExpiresAt != nil && cacheFile != "" {
+		writeCachedCredentials(cacheFile, credentials)
+	}
+
+	return credentials, nil
+}
+
+func runCredentialProcess(command string) (*ProcessCredentials, error) {
+	args, err := shellquote.Split(command)
+	if err != nil || len(args) == 0 {
+		errMsg := fmt.Sprintf("Invalid credential process %q", command)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialProcessTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) // nolint: gosec
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	if err := cmd.Run(); err != nil {
+		errMsg := fmt.Sprintf("Credential process %s failed: %s", args[0], strings.TrimSpace(stderr.String()))
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	credentials := &ProcessCredentials{}
+	if err := json.Unmarshal(stdout.Bytes(), credentials); err != nil {
+		errMsg := fmt.Sprintf("Failed to parse the output of credential process %s", args[0])
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	if credentials.APIKey == "" {
+		errMsg := fmt.Sprintf("Credential process %s returned no api_key", args[0])
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	return credentials, nil
+}
+
+// credentialCacheFile returns the file caching the credentials of the command, which is not part of its name.
+func credentialCacheFile(command string) string {
+	if appConfig.ConfigHome == "" {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(command))
+
+	return filepath.Join(appConfig.ConfigHome, credentialCacheDir, hex.EncodeToString(sum[:])+".json")
+}
+
+func readCachedCredentials(path string) *ProcessCredentials {
+	if path == "" {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	credentials := &ProcessCredentials{}
+	if err := json.Unmarshal(content, credentials); err != nil || credentials.ExpiresAt == nil {
+		return nil
+	}
+
+	return credentials
+}
+
+func writeCachedCredentials(path string, credentials *ProcessCredentials) {
+	content, err := json.Marshal(credentials)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), credentialCacheDirMode); err != nil {
+		logrus.Warnf("Failed to cache credentials: %v", err)
+		return
+	}
+
+	if err := ioutil.WriteFile(path, content, credentialCacheMode); err != nil {
+		logrus.Warnf("Failed to cache credentials: %v", err)
+	}
+}
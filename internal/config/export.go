@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cast"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+// ExportProfiles returns the settings of the profiles in the config file schema, all the profiles if none is given.
+//
+// The api id and key are only exported with includeSecrets, including those in a credential store.
+func ExportProfiles(names []string, includeSecrets bool) (map[string]interface{}, error) {
+	if len(names) == 0 {
+		names = profileNames()
+	}
+
+	settings := map[string]interface{}{versionKey: SchemaVersion}
+
+	for _, name := range names {
+		property, ok := appConfig.Properties[name]
+		if !ok {
+			return nil, profileNotFoundErr(name)
+		}
+
+		profile := make(map[string]interface{})
+
+		for i := 0; i < int(cntOfOptions); i++ {
+			option := Option(i)
+			if option == ActiveUserProfile {
+				continue
+			}
+
+			if value := property.get(option); value != "" {
+				profile[option.String()] = value
+			}
+		}
+
+		if includeSecrets {
+			if err := loadStoredSecrets(name); err != nil {
+				return nil, err
+			}
+
+			profile[CBApiID.String()] = property.CBApiID
+			profile[CBApiKey.String()] = property.CBApiKey
+		}
+
+		settings[name] = profile
+	}
+
+	return settings, nil
+}
+
+// ImportProfiles will validate the settings in the config file schema and add their profiles,
+// the existing profiles are only replaced with overwrite.
+//
+// The imported api id and key are saved in the keyring if it is accessible.
+func ImportProfiles(settings map[string]interface{}, overwrite bool) ([]string, error) {
+	if err := migrateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	// the profiles are imported under valid profile names, so that they can be selected
+	profiles := make(map[string]map[string]interface{})
+
+	for key, value := range settings {
+		profile, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		name := ConvertToValidProfileName(key)
+		if _, exists := appConfig.Properties[name]; exists && !overwrite {
+			errMsg := fmt.Sprintf("User %s already exists, use --overwrite to replace it", name)
+			return nil, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+		}
+
+		profiles[name] = profile
+	}
+
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		profile := profiles[name]
+		appConfig.Properties[name] = &Property{}
+
+		for key, value := range profile {
+			if _, option := Contains(key); option >= 0 {
+				SetConfigByOption(name, option, cast.ToString(value))
+			}
+		}
+
+		saveImportedCredentials(name)
+	}
+
+	return names, nil
+}
+
+// saveImportedCredentials will move the imported api id and key of the profile to the keyring if possible.
+func saveImportedCredentials(name string) {
+	property := appConfig.Properties[name]
+	if !appConfig.AccessToKeyring || property.CBApiID == "" || property.CBApiKey == "" {
+		return
+	}
+
+	_, _ = StoreCredentials(name, property.CBApiID, property.CBApiKey, StoreKeyring)
+}
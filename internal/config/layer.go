@@ -0,0 +1,258 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+// Source is where the effective value of an option comes from.
+type Source string
+
+// The sources of the option values, from the lowest precedence to the highest.
+const (
+	SourceDefault Source = "default"
+	SourceUser    Source = "user"
+	SourceProject Source = "project"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// ProjectConfigName is the name of the project config file, looked up from the working directory to the root.
+var ProjectConfigName = fmt.Sprintf(".%s.yaml", internal.ApplicationName)
+
+// layeredValue is the value of an option overriding the profile, with where it comes from.
+type layeredValue struct {
+	value  string
+	source Source
+	origin string
+}
+
+// EffectiveOption is the effective value of an option of the active profile.
+type EffectiveOption struct {
+	Option string `json:"option"`
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+	// Origin is the file, env variable or flag the value comes from
+	Origin string `json:"origin,omitempty"`
+}
+
+// projectOptions are the build and scan options a project config file can set, the options of the backend,
+// its credentials, the network and the logs are only read from the user config so that a cloned project cannot
+// send the api key to another host or make the client trust another CA.
+var projectOptions = map[Option]bool{
+	DefaultBuildStep:  true,
+	Namespace:         true,
+	FailOn:            true,
+	BaselineFile:      true,
+	CatalogScope:      true,
+	Catalogers:        true,
+	CatalogExclusions: true,
+	SearchArchives:    true,
+}
+
+// pathOptions are the options holding a path, resolved from the directory of the project config file.
+var pathOptions = map[Option]bool{
+	BaselineFile: true,
+}
+
+// FindProjectConfig will walk up from dir to the root and return the first project config file found,
+// skipping the user config file, or an empty string if there is none.
+func FindProjectConfig(dir, userConfigFile string) string {
+	userConfigFile, _ = filepath.Abs(userConfigFile)
+
+	for {
+		candidate := filepath.Join(dir, ProjectConfigName)
+		if stat, err := os.Stat(candidate); err == nil && !stat.IsDir() && candidate != userConfigFile {
+			return candidate
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+
+		dir = parent
+	}
+}
+
+// ReadProjectConfig will read the build and scan options of a project config file, the other options are
+// rejected.
+func ReadProjectConfig(path string) (map[Option]string, error) {
+	fileViper := viper.New()
+	fileViper.SetConfigFile(path)
+
+	if err := fileViper.ReadInConfig(); err != nil {
+		errMsg := fmt.Sprintf("Failed to read project config file %s", path)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	options := make(map[Option]string)
+
+	var problems []string
+
+	for key, value := range fileViper.AllSettings() {
+		if key == versionKey {
+			if version, err := cast.ToIntE(value); err != nil || version > SchemaVersion {
+				problems = append(problems, fmt.Sprintf("unsupported version %v", value))
+			}
+
+			continue
+		}
+
+		valid, option := Contains(key)
+
+		switch {
+		case !valid && option != CBApiID && option != CBApiKey:
+			problems = append(problems, fmt.Sprintf("unknown key %q", key))
+		case !projectOptions[option]:
+			problems = append(problems, fmt.Sprintf("%s is ignored, it is only read from the user config", key))
+		default:
+			options[option] = cast.ToString(value)
+
+			if pathOptions[option] && options[option] != "" && !filepath.IsAbs(options[option]) {
+				options[option] = filepath.Join(filepath.Dir(path), options[option])
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+
+		errMsg := fmt.Sprintf("Invalid project config %s:\n  %s", path, strings.Join(problems, "\n  "))
+
+		return options, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	return options, nil
+}
+
+// loadProjectConfig will layer the options of the project config file found from the working directory, if any.
+func loadProjectConfig() {
+	appConfig.ProjectConfigFile = ""
+
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+
+	path := FindProjectConfig(wd, viper.ConfigFileUsed())
+	if path == "" {
+		return
+	}
+
+	logrus.Println("Using project config file: ", path)
+	appConfig.ProjectConfigFile = path
+
+	// the valid options are still used if some are not
+	options, err := ReadProjectConfig(path)
+	if err != nil {
+		logrus.Warnln(err)
+	}
+
+	for option, value := range options {
+		appConfig.layers[option] = layeredValue{value: value, source: SourceProject, origin: path}
+	}
+}
+
+// overrideValue returns the value of an override flag, telling if it is from the flag or its env variable.
+func overrideValue(key, value string) layeredValue {
+	envName := strings.ToUpper(fmt.Sprintf("%s_%s", internal.ApplicationName,
+		strings.ReplaceAll(key, cliFlagDelimeter, configValueDelimeter)))
+
+	if envValue, ok := os.LookupEnv(envName); ok && envValue == value {
+		return layeredValue{value: value, source: SourceEnv, origin: envName}
+	}
+
+	return layeredValue{value: value, source: SourceFlag, origin: "--" + key}
+}
+
+// EffectiveConfig returns the effective value of every option of the active profile and where it comes from,
+// the api key is masked.
+func EffectiveConfig() []EffectiveOption {
+	return optionsWithLayers(appConfig.layers)
+}
+
+// SavedConfig returns the value of every option saved in the active profile, the api key is masked.
+func SavedConfig() []EffectiveOption {
+	return optionsWithLayers(nil)
+}
+
+// optionsWithLayers returns the value of every option of the active profile with the layers applied.
+func optionsWithLayers(layers map[Option]layeredValue) []EffectiveOption {
+	property := appConfig.Properties[appConfig.ActiveUserProfile]
+	options := make([]Option, 0, int(cntOfOptions)+2)
+
+	for i := 0; i < int(cntOfOptions); i++ {
+		if Option(i) != ActiveUserProfile {
+			options = append(options, Option(i))
+		}
+	}
+
+	options = append(options, CBApiID, CBApiKey)
+	result := make([]EffectiveOption, 0, len(options))
+
+	for _, option := range options {
+		effective := EffectiveOption{Option: option.String(), Source: SourceDefault}
+
+		if layered, ok := layers[option]; ok {
+			effective.Value, effective.Source, effective.Origin = layered.value, layered.source, layered.origin
+		} else if value, origin := savedValue(property, option, layers != nil); value != "" {
+			effective.Value, effective.Source, effective.Origin = value, SourceUser, origin
+		}
+
+		if option == CBApiKey {
+			effective.Value = MaskSecret(effective.Value)
+		}
+
+		result = append(result, effective)
+	}
+
+	return result
+}
+
+// savedValue returns the value of an option saved in the profile and where it comes from,
+// the credentials are resolved from the key file and credential process if external is set.
+func savedValue(property *Property, option Option, external bool) (string, string) {
+	value, origin := property.get(option), ""
+
+	if external && (option == CBApiID || option == CBApiKey) {
+		value, origin = resolveCredential(option)
+	} else if property.StoresCredentials() && (option == CBApiID || option == CBApiKey) {
+		origin = storeName(previousStore(property))
+	}
+
+	if origin == "" {
+		origin = viper.ConfigFileUsed()
+	}
+
+	return value, origin
+}
+
+// CredentialSource describes where the api key of the active profile comes from.
+func CredentialSource() string {
+	if layered, ok := appConfig.layers[CBApiKey]; ok {
+		return fmt.Sprintf("%s %s", layered.source, layered.origin)
+	}
+
+	value, origin := resolveCredential(CBApiKey)
+
+	switch {
+	case value == "":
+		return "none"
+	case origin == "":
+		return "config file " + viper.ConfigFileUsed()
+	case origin == GetConfig(CBApiKeyFile):
+		return "key file " + origin
+	default:
+		return origin
+	}
+}
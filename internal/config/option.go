@@ -17,6 +17,44 @@ const (
 	OrgKey
 	// DefaultBuildStep in the default build step.
 	DefaultBuildStep
+	// HistoryMaxEntries is the max count of scan results kept in the local history.
+	HistoryMaxEntries
+	// HistoryMaxAgeDays is the max age in days of scan results kept in the local history.
+	HistoryMaxAgeDays
+	// RetryMaxAttempts is the max count of attempts for a request to the backend.
+	RetryMaxAttempts
+	// RetryMaxDelay is the max delay in seconds between two attempts of a request.
+	RetryMaxDelay
+	// ProxyURL is the proxy for the connections to the backend.
+	ProxyURL
+	// NoProxy is the comma-separated hosts connected without the proxy.
+	NoProxy
+	// CABundle is the PEM file of the extra CA certificates trusted for the backend.
+	CABundle
+	// ClientCert is the PEM file of the client certificate for mutual TLS.
+	ClientCert
+	// ClientKey is the PEM file of the client key for mutual TLS.
+	ClientKey
+	// Namespace is the default namespace to validate the images and resources.
+	Namespace
+	// FailOn is the default severity from which the scan exits with a policy violation.
+	FailOn
+	// BaselineFile is the default baseline file of the scan.
+	BaselineFile
+	// CBApiKeyFile is the file holding the carbon black api key.
+	CBApiKeyFile
+	// CredentialProcess is the command printing the carbon black api id and key.
+	CredentialProcess
+	// LogRedactPatterns are the space-separated regular expressions of the secrets redacted from the logs.
+	LogRedactPatterns
+	// CatalogScope is the default layers of the image cataloged: squashed or all-layers.
+	CatalogScope
+	// Catalogers are the default space-separated catalogers enabled, or disabled if prefixed with "-".
+	Catalogers
+	// CatalogExclusions are the default space-separated globs of the paths not cataloged.
+	CatalogExclusions
+	// SearchArchives is the default archives searched for packages: indexed, all or none.
+	SearchArchives
 	cntOfOptions
 
 	// CBApiID is the carbon black api id;
@@ -41,6 +79,44 @@ func (o Option) String() string {
 		return "cb_api_key"
 	case DefaultBuildStep:
 		return "default_build_step"
+	case HistoryMaxEntries:
+		return "history_max_entries"
+	case HistoryMaxAgeDays:
+		return "history_max_age_days"
+	case RetryMaxAttempts:
+		return "retry_max_attempts"
+	case RetryMaxDelay:
+		return "retry_max_delay"
+	case ProxyURL:
+		return "proxy_url"
+	case NoProxy:
+		return "no_proxy"
+	case CABundle:
+		return "ca_bundle"
+	case ClientCert:
+		return "client_cert"
+	case ClientKey:
+		return "client_key"
+	case Namespace:
+		return "namespace"
+	case FailOn:
+		return "fail_on"
+	case BaselineFile:
+		return "baseline_file"
+	case CBApiKeyFile:
+		return "cb_api_key_file"
+	case CredentialProcess:
+		return "credential_process"
+	case LogRedactPatterns:
+		return "log_redact_patterns"
+	case CatalogScope:
+		return "catalog_scope"
+	case Catalogers:
+		return "catalogers"
+	case CatalogExclusions:
+		return "catalog_exclusions"
+	case SearchArchives:
+		return "search_archives"
 	case cntOfOptions:
 		fallthrough
 	default:
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+const (
+	secretMask        = "****"
+	secretVisibleTail = 4
+)
+
+// Profile is the summary of a user profile, the api key is masked. Its credential store is keyring or file,
+// or empty if the credentials are in the config file.
+type Profile struct {
+	Name             string `json:"name"`
+	Active           bool   `json:"active"`
+	SaasURL          string `json:"saas_url"`
+	OrgKey           string `json:"org_key"`
+	CBApiID          string `json:"cb_api_id"`
+	CBApiKey         string `json:"cb_api_key"`
+	AuthByKeyring    bool   `json:"auth_by_keyring"`
+	CredentialStore  string `json:"credential_store"`
+	DefaultBuildStep string `json:"default_build_step"`
+}
+
+// Profiles returns the summary of all the user profiles sorted by name.
+func Profiles() []Profile {
+	profiles := make([]Profile, 0, len(appConfig.Properties))
+
+	for name := range appConfig.Properties {
+		profile, _ := GetProfile(name)
+		profiles = append(profiles, profile)
+	}
+
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+
+	return profiles
+}
+
+// GetProfile returns the summary of an existing user profile.
+func GetProfile(name string) (Profile, error) {
+	property, ok := appConfig.Properties[name]
+	if !ok {
+		return Profile{}, profileNotFoundErr(name)
+	}
+
+	return Profile{
+		Name:             name,
+		Active:           name == appConfig.ActiveUserProfile,
+		SaasURL:          property.SaasURL,
+		OrgKey:           property.OrgKey,
+		CBApiID:          property.CBApiID,
+		CBApiKey:         MaskSecret(property.CBApiKey),
+		AuthByKeyring:    property.AuthByKeyring,
+		CredentialStore:  credentialStore(property),
+		DefaultBuildStep: property.DefaultBuildStep,
+	}, nil
+}
+
+// credentialStore returns the store holding the credentials of the property, or empty for the config file.
+func credentialStore(property *Property) string {
+	if !property.StoresCredentials() {
+		return ""
+	}
+
+	return previousStore(property)
+}
+
+// UseProfile will set an existing user profile as the active one.
+func UseProfile(name string) error {
+	if _, ok := appConfig.Properties[name]; !ok {
+		return profileNotFoundErr(name)
+	}
+
+	appConfig.ActiveUserProfile = name
+
+	return nil
+}
+
+// RenameProfile will rename an existing user profile, the credentials in its credential store are moved as well.
+func RenameProfile(oldName, newName string) error {
+	property, ok := appConfig.Properties[oldName]
+	if !ok {
+		return profileNotFoundErr(oldName)
+	}
+
+	if _, ok := appConfig.Properties[newName]; ok {
+		errMsg := fmt.Sprintf("User %s already exists", newName)
+		return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	if property.StoresCredentials() {
+		if err := moveStoredCredentials(oldName, newName, property); err != nil {
+			return err
+		}
+	}
+
+	appConfig.Properties[newName] = property
+	delete(appConfig.Properties, oldName)
+
+	if appConfig.ActiveUserProfile == oldName {
+		appConfig.ActiveUserProfile = newName
+	}
+
+	return nil
+}
+
+// DeleteProfile will delete an existing user profile with its credentials in its credential store,
+// the default profile becomes active if the deleted one was.
+func DeleteProfile(name string) error {
+	if _, ok := appConfig.Properties[name]; !ok {
+		return profileNotFoundErr(name)
+	}
+
+	if appConfig.Properties[name].AuthByFile {
+		deleteSecrets(StoreEncryptedFile, name)
+	}
+
+	delete(appConfig.Properties, name)
+	deleteSecrets(StoreKeyring, name)
+
+	if name == appConfig.ActiveUserProfile {
+		appConfig.ActiveUserProfile = ConvertToValidProfileName("default")
+	}
+
+	return nil
+}
+
+// MaskSecret will mask a secret but its last characters.
+func MaskSecret(secret string) string {
+	if secret == "" {
+		return ""
+	}
+
+	if len(secret) <= secretVisibleTail*2 {
+		return secretMask
+	}
+
+	return secretMask + secret[len(secret)-secretVisibleTail:]
+}
+
+func profileNotFoundErr(name string) error {
+	errMsg := fmt.Sprintf("User %s is not a valid user, existing users: %s",
+		name, strings.Join(profileNames(), ", "))
+
+	return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+}
+
+func profileNames() []string {
+	names := make([]string, 0, len(appConfig.Properties))
+	for name := range appConfig.Properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cast"
+	"github.com/spf13/viper"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+const (
+	// SchemaVersion is the version of the config file schema written by this cli.
+	SchemaVersion = 1
+
+	versionKey = "version"
+)
+
+// migrations[i] migrates the settings of a config file from the schema version i to i+1.
+var migrations = []func(settings map[string]interface{}){
+	// version 0 allowed the options of the active profile at the top level, move them into the profile
+	func(settings map[string]interface{}) {
+		active := cast.ToString(settings[ActiveUserProfile.String()])
+		if active == "" {
+			active = "default"
+		}
+
+		if !strings.HasPrefix(active, internal.ApplicationName) {
+			active = fmt.Sprintf("%s_%s", internal.ApplicationName, active)
+		}
+
+		for key, value := range settings {
+			if _, isProfile := value.(map[string]interface{}); isProfile || key == ActiveUserProfile.String() {
+				continue
+			}
+
+			if _, option := Contains(key); option < 0 {
+				continue
+			}
+
+			profile, ok := settings[active].(map[string]interface{})
+			if !ok {
+				profile = make(map[string]interface{})
+				settings[active] = profile
+			}
+
+			if _, exists := profile[key]; !exists {
+				profile[key] = value
+			}
+
+			delete(settings, key)
+		}
+	},
+}
+
+// ReadConfigFile will read the settings of a config file and migrate them to the current schema version.
+func ReadConfigFile(path string) (map[string]interface{}, error) {
+	fileViper := viper.New()
+	fileViper.SetConfigFile(path)
+
+	if err := fileViper.ReadInConfig(); err != nil {
+		errMsg := fmt.Sprintf("Failed to read config file %s", path)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	settings := fileViper.AllSettings()
+
+	if err := migrateSettings(settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// migrateSettings will migrate the settings to the current schema version in place,
+// the settings of a newer schema version are refused since they may not be understood.
+func migrateSettings(settings map[string]interface{}) error {
+	version, err := cast.ToIntE(settings[versionKey])
+	if settings[versionKey] != nil && err != nil {
+		errMsg := fmt.Sprintf("Invalid config file version %v", settings[versionKey])
+		return cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	if version > SchemaVersion {
+		errMsg := fmt.Sprintf("Config file version %d is newer than the supported version %d, please upgrade %s",
+			version, SchemaVersion, internal.ApplicationName)
+
+		return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	for ; version < SchemaVersion; version++ {
+		migrations[version](settings)
+	}
+
+	settings[versionKey] = SchemaVersion
+
+	return nil
+}
+
+// ValidateSettings will check the settings of a config file with the current schema version,
+// the error lists all the problems found.
+func ValidateSettings(settings map[string]interface{}) error {
+	var problems []string
+
+	for key, value := range settings {
+		if key == versionKey || key == ActiveUserProfile.String() {
+			continue
+		}
+
+		profile, ok := value.(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown key %q", key))
+			continue
+		}
+
+		problems = append(problems, validateProfile(key, profile)...)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+
+	errMsg := fmt.Sprintf("Invalid config:\n  %s", strings.Join(problems, "\n  "))
+
+	return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+}
+
+func validateProfile(name string, profile map[string]interface{}) []string {
+	var problems []string
+
+	for key := range profile {
+		if _, option := Contains(key); option >= 0 && option != ActiveUserProfile {
+			continue
+		}
+
+		problem := fmt.Sprintf("unknown key %q in profile %s", key, name)
+		if suggestions := SuggestionsFor(key); len(suggestions) > 0 {
+			problem += fmt.Sprintf(", did you mean %s?", strings.Join(suggestions, " or "))
+		}
+
+		problems = append(problems, problem)
+	}
+
+	saasURL := cast.ToString(profile[SaasURL.String()])
+	if saasURL == "" {
+		return problems
+	}
+
+	if u, err := url.Parse(saasURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("malformed saas_url %q in profile %s", saasURL, name))
+	}
+
+	if cast.ToString(profile[OrgKey.String()]) == "" {
+		problems = append(problems, fmt.Sprintf("missing org_key in profile %s", name))
+	}
+
+	return problems
+}
@@ -0,0 +1,453 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/crypto/scrypt"
+)
+
+// The credential stores holding the api id and key instead of the config file.
+const (
+	StoreKeyring       = "keyring"
+	StoreEncryptedFile = "file"
+)
+
+const (
+	// PassphraseEnv is the env variable holding the passphrase of the encrypted credential file.
+	PassphraseEnv = "CBCTL_CREDENTIALS_PASSPHRASE"
+	// CredentialFileName is the name of the encrypted credential file in the config home.
+	CredentialFileName = "credentials.enc"
+
+	encryptedFileMaskValue = "[authenticated by encrypted file]"
+	encryptedFileVersion   = 1
+	encryptedFileMode      = 0600
+	encryptionKeyLength    = 32
+	saltLength             = 16
+	scryptN                = 1 << 15
+	scryptR                = 8
+	scryptP                = 1
+)
+
+// PassphrasePrompt asks the passphrase of the encrypted credential file if it is not in the env, confirm is set
+// when it is asked again to confirm the passphrase of a new file. It is only set by the interactive commands,
+// the other commands fail if the passphrase is not in the env.
+var PassphrasePrompt func(confirm bool) (string, error)
+
+var fileStore = NewEncryptedStore("")
+
+// encryptedFile is the content of the encrypted credential file,
+// the secrets are sealed with AES-256-GCM by a key derived from the passphrase with scrypt.
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptedStore is the credential store in an encrypted file, for the hosts without keyring.
+type EncryptedStore struct {
+	path       string
+	passphrase string
+	// secrets are the secrets by profile and option, nil until the file is unlocked
+	secrets map[string]map[string]string
+	// err is the failure to unlock the file, kept so that the passphrase is not asked again
+	err error
+}
+
+// NewEncryptedStore creates an encrypted credential store in path, the file is only created on the first secret.
+func NewEncryptedStore(path string) *EncryptedStore {
+	return &EncryptedStore{path: path}
+}
+
+// Get returns the secrets of the profile by option.
+func (s *EncryptedStore) Get(profile string) (map[string]string, error) {
+	if err := s.unlock(); err != nil {
+		return nil, err
+	}
+
+	secrets, ok := s.secrets[profile]
+	if !ok {
+		errMsg := fmt.Sprintf("No credentials of %s in %s", profile, s.path)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	return secrets, nil
+}
+
+// Set will save the secrets of the profile, replacing its previous ones.
+func (s *EncryptedStore) Set(profile string, secrets map[string]string) error {
+	if err := s.unlock(); err != nil {
+		return err
+	}
+
+	s.secrets[profile] = secrets
+
+	return s.save()
+}
+
+// Delete will remove the secrets of the profile.
+func (s *EncryptedStore) Delete(profile string) error {
+	if err := s.unlock(); err != nil {
+		return err
+	}
+
+	if _, ok := s.secrets[profile]; !ok {
+		return nil
+	}
+
+	delete(s.secrets, profile)
+
+	return s.save()
+}
+
+// unlock will decrypt the file with the passphrase from the env or the prompt, a missing file is empty and
+// needs no passphrase. A failure is returned again by the next calls, without asking the passphrase again.
+func (s *EncryptedStore) unlock() error {
+	if s.secrets != nil || s.err != nil {
+		return s.err
+	}
+
+	if s.path == "" {
+		return cberr.NewError(cberr.ConfigErr, "No encrypted credential file configured", nil)
+	}
+
+	content, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		s.secrets = make(map[string]map[string]string)
+		return nil
+	} else if err != nil {
+		errMsg := fmt.Sprintf("Failed to read encrypted credential file %s", s.path)
+		s.err = cberr.NewError(cberr.ConfigErr, errMsg, err)
+
+		return s.err
+	}
+
+	if s.passphrase, s.err = readPassphrase(false); s.err != nil {
+		return s.err
+	}
+
+	secrets, err := s.decrypt(content)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to decrypt %s, is the passphrase correct?", s.path)
+		s.err = cberr.NewError(cberr.ConfigErr, errMsg, err)
+
+		return s.err
+	}
+
+	s.secrets = secrets
+
+	return nil
+}
+
+// readPassphrase will read the passphrase from the env, or from the prompt of an interactive command; the
+// passphrase of a new file is asked twice.
+func readPassphrase(create bool) (string, error) {
+	if passphrase := os.Getenv(PassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+
+	if PassphrasePrompt == nil {
+		errMsg := fmt.Sprintf("No passphrase for the encrypted credential file, set it in %s", PassphraseEnv)
+		return "", cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	passphrase, err := PassphrasePrompt(false)
+	if err != nil {
+		return "", cberr.NewError(cberr.ConfigErr, "Failed to read the passphrase of the encrypted credential file", err)
+	}
+
+	if passphrase == "" {
+		return "", cberr.NewError(cberr.ConfigErr, "The passphrase of the encrypted credential file is empty", nil)
+	}
+
+	if !create {
+		return passphrase, nil
+	}
+
+	confirmed, err := PassphrasePrompt(true)
+	if err != nil {
+		return "", cberr.NewError(cberr.ConfigErr, "Failed to read the passphrase of the encrypted credential file", err)
+	}
+
+	if confirmed != passphrase {
+		return "", cberr.NewError(cberr.ConfigErr, "The passphrases of the encrypted credential file do not match", nil)
+	}
+
+	return passphrase, nil
+}
+
+func (s *EncryptedStore) decrypt(content []byte) (map[string]map[string]string, error) {
+	file := encryptedFile{}
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, err
+	}
+
+	if file.Version != encryptedFileVersion {
+		return nil, fmt.Errorf("unsupported version %d", file.Version)
+	}
+
+	aead, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := make(map[string]map[string]string)
+
+	return secrets, json.Unmarshal(plaintext, &secrets)
+}
+
+// save will encrypt the secrets with a new salt and nonce, the file is replaced atomically.
+func (s *EncryptedStore) save() error {
+	// the file did not exist when unlocked, its passphrase is chosen now
+	if s.passphrase == "" {
+		passphrase, err := readPassphrase(true)
+		if err != nil {
+			return err
+		}
+
+		s.passphrase = passphrase
+	}
+
+	content, err := s.encrypt()
+	if err != nil {
+		return cberr.NewError(cberr.ConfigErr, "Failed to encrypt the credentials", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), credentialCacheDirMode); err != nil {
+		errMsg := fmt.Sprintf("Failed to write encrypted credential file %s", s.path)
+		return cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err == nil {
+		_, err = tmp.Write(content)
+
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+
+		if err == nil {
+			err = os.Chmod(tmp.Name(), encryptedFileMode)
+		}
+
+		if err == nil {
+			err = os.Rename(tmp.Name(), s.path)
+		}
+
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}
+
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to write encrypted credential file %s", s.path)
+		return cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return nil
+}
+
+func (s *EncryptedStore) encrypt() ([]byte, error) {
+	plaintext, err := json.Marshal(s.secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	file := encryptedFile{Version: encryptedFileVersion, Salt: make([]byte, saltLength)}
+	if _, err := io.ReadFull(rand.Reader, file.Salt); err != nil {
+		return nil, err
+	}
+
+	aead, err := s.cipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	file.Nonce = make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, file.Nonce); err != nil {
+		return nil, err
+	}
+
+	file.Ciphertext = aead.Seal(nil, file.Nonce, plaintext, nil)
+
+	return json.Marshal(file)
+}
+
+func (s *EncryptedStore) cipher(salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(s.passphrase), salt, scryptN, scryptR, scryptP, encryptionKeyLength)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// StoreCredentials will save the api id and key of the profile in a credential store, so that they are masked
+// in the config file; the keyring is preferred, the encrypted credential file is used if it is not accessible.
+//
+// The store can be forced, the returned one is the store used.
+func StoreCredentials(profile, apiID, apiKey, store string) (string, error) {
+	initUserProperty(profile)
+	property := appConfig.Properties[profile]
+
+	stores := []string{StoreKeyring, StoreEncryptedFile}
+	if store != "" {
+		stores = []string{store}
+	}
+
+	var lastErr error
+
+	for _, s := range stores {
+		if lastErr = saveSecrets(s, profile, apiID, apiKey); lastErr != nil {
+			logrus.Warnf("Cannot save the credentials of %s in the %s: %v", profile, storeName(s), lastErr)
+			continue
+		}
+
+		// the credentials only stay in the store used
+		if property.StoresCredentials() && previousStore(property) != s {
+			deleteSecrets(previousStore(property), profile)
+		}
+
+		property.CBApiID, property.CBApiKey = apiID, apiKey
+		property.AuthByKeyring, property.AuthByFile = s == StoreKeyring, s == StoreEncryptedFile
+		property.secretsLoaded = true
+
+		return s, nil
+	}
+
+	errMsg := fmt.Sprintf("No credential store available for %s", profile)
+
+	return "", cberr.NewError(cberr.ConfigErr, errMsg, lastErr)
+}
+
+// UnstoreCredentials will remove the api id and key of the profile from its credential store, so that they are
+// kept in the config file.
+func UnstoreCredentials(profile string) {
+	property, ok := appConfig.Properties[profile]
+	if !ok || !property.StoresCredentials() {
+		return
+	}
+
+	deleteSecrets(previousStore(property), profile)
+	property.AuthByKeyring, property.AuthByFile = false, false
+}
+
+// StoresCredentials reports if the api id and key of the property are in a credential store instead of the
+// config file.
+func (p *Property) StoresCredentials() bool {
+	return p.AuthByKeyring || p.AuthByFile
+}
+
+// loadStoredSecrets will read the api id and key of the profile from the encrypted credential file,
+// they are only read on demand since the passphrase may be prompted.
+func loadStoredSecrets(name string) error {
+	property, ok := appConfig.Properties[name]
+	if !ok || !property.AuthByFile || property.secretsLoaded {
+		return nil
+	}
+
+	secrets, err := fileStore.Get(name)
+	if err != nil {
+		return err
+	}
+
+	property.CBApiID, property.CBApiKey = secrets[CBApiID.String()], secrets[CBApiKey.String()]
+	property.secretsLoaded = true
+
+	return nil
+}
+
+// moveStoredCredentials will move the api id and key of the profile to its new name in its credential store.
+func moveStoredCredentials(oldName, newName string, property *Property) error {
+	if err := loadStoredSecrets(oldName); err != nil {
+		return err
+	}
+
+	store := previousStore(property)
+
+	if err := saveSecrets(store, newName, property.CBApiID, property.CBApiKey); err != nil {
+		deleteSecrets(store, newName)
+
+		errMsg := fmt.Sprintf("Failed to move the credentials of %s in the %s", oldName, storeName(store))
+
+		return cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	deleteSecrets(store, oldName)
+
+	return nil
+}
+
+func saveSecrets(store, profile, apiID, apiKey string) error {
+	switch store {
+	case StoreKeyring:
+		errKey := keyring.Set(profile, CBApiKey.String(), apiKey)
+		errID := keyring.Set(profile, CBApiID.String(), apiID)
+
+		if errKey != nil || errID != nil {
+			deleteSecrets(StoreKeyring, profile)
+
+			if errKey != nil {
+				return errKey
+			}
+
+			return errID
+		}
+
+		return nil
+	case StoreEncryptedFile:
+		return fileStore.Set(profile, map[string]string{CBApiID.String(): apiID, CBApiKey.String(): apiKey})
+	default:
+		return fmt.Errorf("unknown credential store %q", store)
+	}
+}
+
+func deleteSecrets(store, profile string) {
+	switch store {
+	case StoreKeyring:
+		_ = keyring.Delete(profile, CBApiKey.String())
+		_ = keyring.Delete(profile, CBApiID.String())
+	case StoreEncryptedFile:
+		if err := fileStore.Delete(profile); err != nil {
+			logrus.Warnf("Cannot remove the credentials of %s from the encrypted file: %v", profile, err)
+		}
+	}
+}
+
+// previousStore returns the store holding the credentials of the property.
+func previousStore(property *Property) string {
+	if property.AuthByFile {
+		return StoreEncryptedFile
+	}
+
+	return StoreKeyring
+}
+
+func storeName(store string) string {
+	if store == StoreEncryptedFile {
+		return "encrypted file"
+	}
+
+	return store
+}
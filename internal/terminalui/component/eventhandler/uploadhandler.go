@@ -0,0 +1,15 @@
+package eventhandler
+
+import (
+	"github.com/gookit/color"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui/component/frame"
+)
+
+// UploadStartedHandler periodically writes the bytes of the software bills of material uploaded,
+// there is no bar as the size of the payload is unknown.
+func (h *Handler) UploadStartedHandler(line *frame.Line, value interface{}) error {
+	pendingMsg := color.Bold.Sprint("Uploading sbom")
+	completedMsg := color.Bold.Sprint("Uploaded sbom")
+
+	return h.renderStatusString(pendingMsg, completedMsg, false, true, line, value)
+}
@@ -7,23 +7,38 @@ import (
 
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui/dynamicui"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui/jsonui"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui/plainui"
 	"golang.org/x/term"
 )
 
+// All the displays which can be selected with the ui flag.
+const (
+	UIAuto    = "auto"
+	UIPlain   = "plain"
+	UIDynamic = "dynamic"
+	UIJSON    = "json"
+)
+
 // Display is the interface with the function for displaying events.
 type Display interface {
 	DisplayEvents()
 }
 
-// NewDisplay will select a display handler based on the environment.
+// NewDisplay will select a display handler based on the ui option and the environment.
 func NewDisplay() Display {
 	isStdoutATty := term.IsTerminal(int(os.Stdout.Fd()))
 	isStderrATty := term.IsTerminal(int(os.Stderr.Fd()))
 	notATerminal := !isStderrATty && !isStdoutATty
 
-	switch {
-	case notATerminal || runtime.GOOS == "windows" || config.Config().CliOpt.PlainMode:
+	switch ui := config.Config().CliOpt.UI; {
+	case ui == UIJSON:
+		return jsonui.NewDisplay(os.Stdout)
+	case ui == UIPlain || config.Config().CliOpt.PlainMode:
+		return plainui.NewDisplay()
+	case ui == UIDynamic:
+		return dynamicui.NewDisplay()
+	case notATerminal || runtime.GOOS == "windows":
 		return plainui.NewDisplay()
 	default:
 		return dynamicui.NewDisplay()
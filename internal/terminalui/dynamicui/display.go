@@ -76,7 +76,8 @@ func (d Display) DisplayEvents() {
 		}
 
 		if exitCode > 0 {
-			os.Exit(exitCode)
+			// exit through logrus to run the registered exit handlers
+			logrus.Exit(exitCode)
 		}
 	}()
 
@@ -112,15 +113,38 @@ eventLoop:
 			displayErr = handler.CatalogerStartedHandler(fr.Append(), e.Value())
 		case bus.ScanStarted:
 			displayErr = handler.AnalyzeStartedHandler(fr.Append(), e.Value())
+		case bus.UploadStarted:
+			displayErr = handler.UploadStartedHandler(fr.Append(), e.Value())
 		case bus.ScanFinished, bus.ValidateFinishedWithViolations:
 			errorMsg := "failed to show vulnerability results:"
 			displayErr = displayResults(errorMsg, fr, wg, e)
+		case bus.RemediationFinished:
+			errorMsg := "failed to show remediation:"
+			displayErr = displayResults(errorMsg, fr, wg, e)
+		case bus.DiffFinished:
+			errorMsg := "failed to show image difference:"
+			displayErr = displayResults(errorMsg, fr, wg, e)
 		case bus.PrintSBOM:
 			errorMsg := "failed to show packages:"
 			displayErr = displayResults(errorMsg, fr, wg, e)
 		case bus.PrintPayload:
 			errorMsg := "failed to show payload:"
 			displayErr = displayResults(errorMsg, fr, wg, e)
+		case bus.PrintHistory:
+			errorMsg := "failed to show history:"
+			displayErr = displayResults(errorMsg, fr, wg, e)
+		case bus.DoctorFinished:
+			errorMsg := "failed to show diagnostics:"
+			displayErr = displayResults(errorMsg, fr, wg, e)
+		case bus.AttestationVerified:
+			errorMsg := "failed to show verified attestations:"
+			displayErr = displayResults(errorMsg, fr, wg, e)
+		case bus.PrintReferrers:
+			errorMsg := "failed to show referrers:"
+			displayErr = displayResults(errorMsg, fr, wg, e)
+		case bus.PrintProfiles:
+			errorMsg := "failed to show user profiles:"
+			displayErr = displayResults(errorMsg, fr, wg, e)
 		case bus.ReadLayer:
 			fallthrough
 		default:
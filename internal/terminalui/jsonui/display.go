@@ -0,0 +1,270 @@
+// Package jsonui provides display handler writing every event as one json object per line
+package jsonui
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anchore/stereoscope/pkg/image/docker"
+	"github.com/anchore/syft/syft/pkg/cataloger"
+	"github.com/gookit/color"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/logtool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+	"github.com/wagoodman/go-progress"
+)
+
+// interval is the interval between two progress records of a running stage.
+const interval = time.Second
+
+// Record is the json object written for each event, and for the progress of the running stages.
+type Record struct {
+	Time      time.Time     `json:"time"`
+	ElapsedMs int64         `json:"elapsed_ms"`
+	Type      bus.EventType `json:"type"`
+	Message   string        `json:"message,omitempty"`
+	Stage     string        `json:"stage,omitempty"`
+	Progress  *Progress     `json:"progress,omitempty"`
+	Error     *Error        `json:"error,omitempty"`
+	Title     string        `json:"title,omitempty"`
+	Footer    string        `json:"footer,omitempty"`
+	Result    interface{}   `json:"result,omitempty"`
+	Output    string        `json:"output,omitempty"`
+	End       bool          `json:"end,omitempty"`
+}
+
+// Progress is the progress of a running stage.
+type Progress struct {
+	Current   int64 `json:"current"`
+	Size      int64 `json:"size,omitempty"`
+	Packages  int64 `json:"packages,omitempty"`
+	Completed bool  `json:"completed"`
+	// DurationMs is the duration of the stage, set once it is completed
+	DurationMs int64 `json:"duration_ms,omitempty"`
+}
+
+// Error is the error which shuts down the cli.
+type Error struct {
+	Code     cberr.Code `json:"code"`
+	Message  string     `json:"message"`
+	ExitCode int        `json:"exit_code"`
+}
+
+// Display will help us handle all the incoming events and write them as json lines.
+type Display struct {
+	output io.Writer
+	start  time.Time
+	lock   sync.Mutex
+}
+
+// NewDisplay will initialize a display instance writing to the output.
+func NewDisplay(output io.Writer) *Display {
+	return &Display{
+		output: output,
+		start:  time.Now(),
+	}
+}
+
+// DisplayEvents will read events from channel, and write them as json lines.
+func (d *Display) DisplayEvents() {
+	var (
+		displayErr error
+		exitCode   = 0
+		wg         = &sync.WaitGroup{}
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer func() {
+		cancel()
+		wg.Wait()
+
+		if displayErr != nil {
+			msg := "Failed to show the ui during the whole process"
+			e := cberr.NewError(cberr.DisplayErr, msg, displayErr)
+			_, _ = fmt.Fprintln(os.Stderr, msg)
+			exitCode = e.ExitCode()
+
+			logrus.Errorln(e)
+		}
+
+		if exitCode > 0 {
+			// exit through logrus to run the registered exit handlers
+			logrus.Exit(exitCode)
+		}
+	}()
+
+	for e := range bus.EventChan() {
+		if e.Type() == bus.ReadLayer {
+			continue
+		}
+
+		record := d.newRecord(e.Type())
+		record.End = e.IsEnd()
+
+		switch value := e.Value().(type) {
+		case presenter.Presenter:
+			displayErr = setResult(&record, value)
+		case cataloger.Monitor:
+			d.stream(ctx, wg, e.Type(), func(r *Record) { r.Progress = catalogerProgressOf(value) })
+		case *docker.PullStatus:
+			d.stream(ctx, wg, e.Type(), func(r *Record) { r.Progress = pullProgressOf(value) })
+		case progress.Progressable:
+			setStage(&record, value)
+			d.stream(ctx, wg, e.Type(), func(r *Record) {
+				r.Progress = progressOf(value)
+				setStage(r, value)
+			})
+		case string:
+			record.Message = color.ClearCode(value)
+		}
+
+		if errEvent, ok := e.(*bus.ErrorEvent); ok {
+			exitCode = errEvent.ExitCode()
+			record.Message = ""
+			record.Error = &Error{
+				Code:     cberr.ErrorCode(errEvent.Err()),
+				Message:  logtool.Redact(cberr.ErrorMessage(errEvent.Err())),
+				ExitCode: exitCode,
+			}
+		}
+
+		if e.IsEnd() {
+			// the last progress records are written before the end of the output
+			cancel()
+			wg.Wait()
+		}
+
+		if err := d.write(record); err != nil && displayErr == nil {
+			displayErr = err
+		}
+
+		if e.IsEnd() || displayErr != nil {
+			break
+		}
+	}
+}
+
+// newRecord will init a record for the event type at the current time.
+func (d *Display) newRecord(eventType bus.EventType) Record {
+	now := time.Now()
+
+	return Record{
+		Time:      now.UTC(),
+		ElapsedMs: now.Sub(d.start).Milliseconds(),
+		Type:      eventType,
+	}
+}
+
+// write will encode the record as a single line on the output.
+func (d *Display) write(record Record) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	enc := json.NewEncoder(d.output)
+	// prevent > and < from being escaped in the payload
+	enc.SetEscapeHTML(false)
+
+	return enc.Encode(record)
+}
+
+// stream will write the progress of a running stage at each interval until it is completed,
+// the last progress is also written if the output ends before.
+func (d *Display) stream(ctx context.Context, wg *sync.WaitGroup, t bus.EventType, snapshot func(*Record)) {
+	started := time.Now()
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for {
+			done := false
+
+			select {
+			case <-ctx.Done():
+				done = true
+			case <-time.After(interval):
+			}
+
+			record := d.newRecord(t)
+			snapshot(&record)
+
+			if record.Progress.Completed {
+				record.Progress.DurationMs = time.Since(started).Milliseconds()
+			}
+
+			_ = d.write(record)
+
+			if done || record.Progress.Completed {
+				return
+			}
+		}
+	}()
+}
+
+// progressOf will take the current progress of the progressable.
+func progressOf(p progress.Progressable) *Progress {
+	return &Progress{
+		Current:   p.Current(),
+		Size:      p.Size(),
+		Completed: progress.IsCompleted(p),
+	}
+}
+
+// catalogerProgressOf will take the processed files and discovered packages of the cataloger.
+func catalogerProgressOf(m cataloger.Monitor) *Progress {
+	return &Progress{
+		Current:   m.FilesProcessed.Current(),
+		Packages:  m.PackagesDiscovered.Current(),
+		Completed: progress.IsErrCompleted(m.FilesProcessed.Error()) && progress.IsErrCompleted(m.PackagesDiscovered.Error()),
+	}
+}
+
+// pullProgressOf will take the downloaded size of all the layers of the image.
+func pullProgressOf(status *docker.PullStatus) *Progress {
+	prog := &Progress{Completed: status.Complete()}
+
+	for _, layer := range status.Layers() {
+		download := status.Current(layer).DownloadProgress
+		prog.Current += download.Current()
+		prog.Size += download.Size()
+	}
+
+	return prog
+}
+
+// setStage will set the stage of the record if the progressable has stages.
+func setStage(record *Record, p progress.Progressable) {
+	if staged, ok := p.(progress.Stager); ok {
+		record.Stage = color.ClearCode(staged.Stage())
+	}
+}
+
+// setResult will set the raw result of the presenter, or its output if it has no raw result.
+func setResult(record *Record, pres presenter.Presenter) error {
+	record.Title = color.ClearCode(pres.Title())
+	record.Footer = color.ClearCode(pres.Footer())
+
+	if result := presenter.Result(pres); result != nil {
+		record.Result = result
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := pres.Present(&buf); err != nil {
+		return fmt.Errorf("failed to show results: %v", err)
+	}
+
+	record.Output = buf.String()
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package jsonui_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/terminalui/jsonui"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter"
+	"github.com/wagoodman/go-progress"
+)
+
+type result struct {
+	Image string `json:"image"`
+}
+
+func (r result) Title() string  { return "Scan result" }
+func (r result) Footer() string { return "" }
+
+func TestDisplayEvents(t *testing.T) {
+	stage := &progress.Manual{}
+	stage.SetTotal(3)
+	stage.Set(3)
+	stage.SetCompleted()
+
+	events := make(chan bus.Event, 4)
+	bus.SetEventChan(events)
+
+	defer bus.SetEventChan(nil)
+
+	bus.Publish(bus.NewMessageEvent("Start scan", false))
+	bus.Publish(bus.NewEvent(bus.ReadImage, stage, false))
+	bus.Publish(bus.NewEvent(bus.ReadLayer, stage, false))
+	bus.Publish(bus.NewEvent(bus.ScanFinished,
+		presenter.NewPresenter(result{Image: "alpine:3"}, presenter.Option{OutputFormat: "json"}), true))
+
+	var output bytes.Buffer
+
+	jsonui.NewDisplay(&output).DisplayEvents()
+
+	var records []map[string]interface{}
+
+	scanner := bufio.NewScanner(&output)
+	for scanner.Scan() {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record), scanner.Text())
+		require.Contains(t, record, "time")
+		require.Contains(t, record, "elapsed_ms")
+		records = append(records, record)
+	}
+
+	require.Len(t, records, 4)
+	require.Equal(t, string(bus.NewMessageDetected), records[0]["type"])
+	require.Equal(t, "Start scan", records[0]["message"])
+	require.Equal(t, string(bus.ReadImage), records[1]["type"])
+
+	// the progress of the stage is written before the final result
+	require.Equal(t, string(bus.ReadImage), records[2]["type"])
+	require.Equal(t, map[string]interface{}{"current": 3.0, "size": 3.0, "completed": true},
+		withoutDuration(records[2]["progress"]))
+
+	require.Equal(t, string(bus.ScanFinished), records[3]["type"])
+	require.Equal(t, "Scan result", records[3]["title"])
+	require.Equal(t, map[string]interface{}{"image": "alpine:3"}, records[3]["result"])
+	require.Equal(t, true, records[3]["end"])
+}
+
+func withoutDuration(value interface{}) interface{} {
+	prog, ok := value.(map[string]interface{})
+	if ok {
+		delete(prog, "duration_ms")
+	}
+
+	return prog
+}
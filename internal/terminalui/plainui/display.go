@@ -62,7 +62,8 @@ func (d Display) DisplayEvents() {
 		}
 
 		if exitCode > 0 {
-			os.Exit(exitCode)
+			// exit through logrus to run the registered exit handlers
+			logrus.Exit(exitCode)
 		}
 	}()
 
@@ -98,12 +99,25 @@ eventLoop:
 		case bus.ScanStarted:
 			msg := "Analyzing image..."
 			displayErr = printMessageOnStderr(msg)
-		case bus.ScanFinished, bus.ValidateFinishedWithViolations:
+		case bus.UploadStarted:
+			msg := "Uploading software bills of material..."
+			displayErr = printMessageOnStderr(msg)
+		case bus.ScanFinished, bus.ValidateFinishedWithViolations, bus.RemediationFinished, bus.DiffFinished:
 			displayErr = displayResults(e)
 		case bus.PrintSBOM:
 			displayErr = displayResults(e)
 		case bus.PrintPayload:
 			displayErr = displayResults(e)
+		case bus.PrintHistory:
+			displayErr = displayResults(e)
+		case bus.DoctorFinished:
+			displayErr = displayResults(e)
+		case bus.AttestationVerified:
+			displayErr = displayResults(e)
+		case bus.PrintReferrers:
+			displayErr = displayResults(e)
+		case bus.PrintProfiles:
+			displayErr = displayResults(e)
 		case bus.ReadLayer:
 			fallthrough
 		default:
@@ -0,0 +1,163 @@
+package httptool
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxAttempts is the default count of attempts for a request, including the first one.
+	DefaultMaxAttempts = 4
+	// DefaultMaxDelay is the default cap of the delay between two attempts.
+	DefaultMaxDelay = 30 * time.Second
+
+	defaultBaseDelay = time.Second
+)
+
+// defaultRetryPolicy is used by the sessions created after it is set.
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: DefaultMaxAttempts,
+	BaseDelay:   defaultBaseDelay,
+	MaxDelay:    DefaultMaxDelay,
+}
+
+// RetryPolicy is how the transient failures of a request are retried.
+//
+// The delay grows exponentially from BaseDelay with a jitter, and never exceeds MaxDelay,
+// a Retry-After header from the server is honoured within MaxDelay.
+type RetryPolicy struct {
+	// MaxAttempts is the count of attempts including the first one, 1 means no retry
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// SetDefaultRetryPolicy will set the retry policy used by the sessions created afterwards.
+func SetDefaultRetryPolicy(policy RetryPolicy) {
+	defaultRetryPolicy = policy
+}
+
+// ParseRetryPolicy will parse the retry policy from the config values, an empty or invalid value uses the default.
+func ParseRetryPolicy(maxAttempts, maxDelaySeconds string) RetryPolicy {
+	policy := RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   defaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+	}
+
+	if maxAttempts != "" {
+		if parsed, err := strconv.Atoi(maxAttempts); err == nil && parsed > 0 {
+			policy.MaxAttempts = parsed
+		} else {
+			logrus.Warnf("Invalid retry max attempts %q, using the default %d", maxAttempts, DefaultMaxAttempts)
+		}
+	}
+
+	if maxDelaySeconds != "" {
+		if parsed, err := strconv.Atoi(maxDelaySeconds); err == nil && parsed >= 0 {
+			policy.MaxDelay = time.Duration(parsed) * time.Second
+		} else {
+			logrus.Warnf("Invalid retry max delay %q, using the default %v", maxDelaySeconds, DefaultMaxDelay)
+		}
+	}
+
+	if policy.BaseDelay > policy.MaxDelay {
+		policy.BaseDelay = policy.MaxDelay
+	}
+
+	return policy
+}
+
+// shouldRetry returns true if a failed attempt can be retried.
+//
+// Only the idempotent methods are retried on connection failures and gateway errors,
+// a 429 response means the request is not processed, so any method can be retried.
+func shouldRetry(method string, statusCode int, connectionFailed bool) bool {
+	if statusCode == http.StatusTooManyRequests {
+		return true
+	}
+
+	if !isIdempotent(method) {
+		return false
+	}
+
+	if connectionFailed {
+		return true
+	}
+
+	switch statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// delay returns the wait before the given retry (starting from 1).
+func (p RetryPolicy) delay(retry int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if retryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+
+		return retryAfter
+	}
+
+	backoff := p.BaseDelay << (retry - 1)
+	if backoff > p.MaxDelay || backoff <= 0 {
+		backoff = p.MaxDelay
+	}
+
+	// full jitter on the upper half, so the retries of different clients spread out
+	half := backoff / 2 // nolint: gomnd
+	if half <= 0 {
+		return backoff
+	}
+
+	return half + time.Duration(rand.Int63n(int64(half))) // nolint: gosec
+}
+
+// parseRetryAfter will parse the Retry-After header, in either seconds or http date.
+func parseRetryAfter(header http.Header, now time.Time) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+
+	return 0
+}
+
+// wait returns false if ctx is done before the delay.
+func wait(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
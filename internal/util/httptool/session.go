@@ -2,6 +2,7 @@ package httptool
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/logtool"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 )
 
@@ -28,6 +30,7 @@ func (c CarbonBlackAccessInfo) GetToken() string {
 type RequestSession struct {
 	client       http.Client
 	cbAccessInfo *CarbonBlackAccessInfo
+	retryPolicy  RetryPolicy
 }
 
 // NewRequestSession creates a new request session.
@@ -37,30 +40,102 @@ func NewRequestSession(apiID, apiKey string) *RequestSession {
 		apiKey: apiKey,
 	}
 
+	// the key must never show up in the logs, neither the token holding it
+	logtool.AddSecret(apiKey)
+	logtool.AddSecret(cbAccessInfo.GetToken())
+
 	return &RequestSession{
 		client: http.Client{
-			Timeout: 300 * time.Second,
+			Timeout:   300 * time.Second,
+			Transport: defaultTransport,
 		},
 		cbAccessInfo: cbAccessInfo,
+		retryPolicy:  defaultRetryPolicy,
 	}
 }
 
+// SetRetryPolicy will set the retry policy of the session.
+func (r *RequestSession) SetRetryPolicy(policy RetryPolicy) {
+	r.retryPolicy = policy
+}
+
 // RequestData will request data via method to url with payload.
 func (r RequestSession) RequestData(method, url string, payload interface{}) (int, []byte, error) {
+	return r.RequestDataWithContext(context.Background(), method, url, payload)
+}
+
+// RequestDataWithContext will request data via method to url with payload, the request is aborted once ctx is done.
+//
+// The transient failures are retried by the retry policy of the session, the final error records the attempts.
+func (r RequestSession) RequestDataWithContext(
+	ctx context.Context, method, url string, payload interface{},
+) (int, []byte, error) {
+	return r.requestWithRetry(ctx, method, url, payload, nil)
+}
+
+// requestWithRetry will make the attempts of the request, the payload is streamed if stream is set.
+func (r RequestSession) requestWithRetry(
+	ctx context.Context, method, url string, payload interface{}, stream *StreamOptions,
+) (int, []byte, error) {
+	maxAttempts := r.retryPolicy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		statusCode, respBody, retryAfter, err := r.requestOnce(ctx, method, url, payload, stream)
+		if err == nil {
+			return statusCode, respBody, nil
+		}
+
+		connectionFailed := cberr.ErrorCode(err) == cberr.HTTPConnectionErr
+		if attempt >= maxAttempts || !shouldRetry(method, statusCode, connectionFailed) {
+			if attempt > 1 {
+				msg := fmt.Sprintf("Request failed after %d attempts: [%s] %s", attempt, method, url)
+				err = cberr.NewError(cberr.ErrorCode(err), msg, err)
+			}
+
+			return statusCode, respBody, err
+		}
+
+		delay := r.retryPolicy.delay(attempt, retryAfter)
+		logrus.Debugf("Attempt %d/%d failed: [%s] %s: %v; retrying in %v",
+			attempt, maxAttempts, method, url, err, delay)
+
+		if !wait(ctx, delay) {
+			msg := fmt.Sprintf("Request interrupted after %d attempts: [%s] %s", attempt, method, url)
+			return 0, nil, cberr.NewError(cberr.InterruptedErr, msg, ctx.Err())
+		}
+	}
+}
+
+// requestOnce will make a single attempt of the request, it also returns the delay asked by the Retry-After header.
+func (r RequestSession) requestOnce(
+	ctx context.Context, method, url string, payload interface{}, stream *StreamOptions,
+) (int, []byte, time.Duration, error) {
 	var msg string
 
-	req, err := r.generateRequest(method, url, payload)
+	req, err := r.generateRequest(method, url, payload, stream)
 	if err != nil {
-		return 0, nil, err
+		return 0, nil, 0, err
+	}
+
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if ctx.Err() != nil {
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+
+		msg = fmt.Sprintf("Request interrupted: [%s] %s", method, url)
+		return 0, nil, 0, cberr.NewError(cberr.InterruptedErr, msg, ctx.Err())
 	}
 
-	resp, err := r.client.Do(req)
 	if err != nil || resp == nil {
 		msg = "Failed to establish connection to Carbon Black Cloud, please check your config"
 		e := cberr.NewError(cberr.HTTPConnectionErr, msg, err)
 		logrus.Errorf("Failed to retrieve response: [%s] %s: %v", method, url, e)
 
-		return 0, nil, e
+		return 0, nil, 0, e
 	}
 
 	defer func() {
@@ -75,28 +150,30 @@ func (r RequestSession) RequestData(method, url string, payload interface{}) (in
 		e := cberr.NewError(cberr.HTTPConnectionErr, msg, err)
 		logrus.Errorln(e)
 
-		return resp.StatusCode, nil, e
+		return resp.StatusCode, nil, 0, e
 	}
 
 	switch c := resp.StatusCode; {
 	case c == http.StatusUnauthorized || c == http.StatusForbidden:
 		msg = "The requested resource is restricted, please check API access"
-		return c, nil, cberr.NewError(cberr.HTTPNotAllowedErr, msg, nil)
+		return c, nil, 0, cberr.NewError(cberr.HTTPNotAllowedErr, msg, nil)
 	case c == http.StatusNotFound:
 		msg = "The requested resource not found"
-		return c, nil, cberr.NewError(cberr.HTTPNotFoundErr, msg, nil)
+		return c, nil, 0, cberr.NewError(cberr.HTTPNotFoundErr, msg, nil)
 	case c >= http.StatusMultipleChoices:
 		msg = fmt.Sprintf("Unsuccessful %d response", c)
 		e := cberr.NewError(cberr.HTTPUnsuccessfulResponseErr, msg, nil)
 		logrus.Errorf("Unsuccessful response received: %v; error: %v", string(respBody), e.Error())
 
-		return c, respBody, e
+		return c, respBody, parseRetryAfter(resp.Header, time.Now()), e
 	default:
-		return c, respBody, nil
+		return c, respBody, 0, nil
 	}
 }
 
-func (r RequestSession) generateRequest(method, url string, payload interface{}) (*http.Request, error) {
+func (r RequestSession) generateRequest(
+	method, url string, payload interface{}, stream *StreamOptions,
+) (*http.Request, error) {
 	var requester *http.Request
 
 	switch method {
@@ -108,6 +185,22 @@ func (r RequestSession) generateRequest(method, url string, payload interface{})
 
 		requester = req
 	case http.MethodPost, http.MethodPatch, http.MethodPut:
+		if stream != nil {
+			req, err := http.NewRequest(method, url, stream.newBody(payload))
+			if err != nil {
+				return &http.Request{}, err
+			}
+
+			requester = req
+			requester.Header.Set("Content-Type", "application/json")
+
+			if stream.Compress {
+				requester.Header.Set("Content-Encoding", "gzip")
+			}
+
+			break
+		}
+
 		buffer := new(bytes.Buffer)
 
 		if err := json.NewEncoder(buffer).Encode(payload); err != nil {
@@ -0,0 +1,98 @@
+package httptool
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	progress "github.com/wagoodman/go-progress"
+)
+
+// StreamOptions are the options of a request whose payload is encoded while it is sent.
+type StreamOptions struct {
+	// Compress will gzip the payload and set the Content-Encoding header
+	Compress bool
+	// Progress, if set, records the bytes written to the request body, the total is unknown
+	Progress *progress.Manual
+}
+
+// StreamDataWithContext will request data via method to url with payload like RequestDataWithContext,
+// but the JSON encoding of the payload is streamed to the request instead of being buffered in memory.
+//
+// If the server rejects the compressed payload, the request is sent again uncompressed.
+func (r RequestSession) StreamDataWithContext(
+	ctx context.Context, method, url string, payload interface{}, opts StreamOptions,
+) (int, []byte, error) {
+	if opts.Progress != nil {
+		// the payload is not encoded ahead only to know its size
+		opts.Progress.SetTotal(-1)
+	}
+
+	statusCode, respBody, err := r.requestWithRetry(ctx, method, url, payload, &opts)
+	if err != nil && opts.Compress && isEncodingRejected(statusCode) {
+		logrus.Warnf("Compressed payload rejected with %d response: [%s] %s; retrying uncompressed",
+			statusCode, method, url)
+
+		opts.Compress = false
+		statusCode, respBody, err = r.requestWithRetry(ctx, method, url, payload, &opts)
+	}
+
+	return statusCode, respBody, err
+}
+
+// isEncodingRejected returns true if the status code may mean the server can not decode a gzip payload.
+func isEncodingRejected(statusCode int) bool {
+	return statusCode == http.StatusUnsupportedMediaType || statusCode == http.StatusBadRequest
+}
+
+// newBody will start encoding the payload to a pipe, the encoding stops once the request closes the body.
+func (o StreamOptions) newBody(payload interface{}) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	if o.Progress != nil {
+		// every attempt sends the payload from the start
+		o.Progress.Set(0)
+	}
+
+	go func() {
+		var (
+			w  io.Writer = writer
+			gz *gzip.Writer
+		)
+
+		if o.Progress != nil {
+			w = &progressWriter{w: w, progress: o.Progress}
+		}
+
+		if o.Compress {
+			gz = gzip.NewWriter(w)
+			w = gz
+		}
+
+		err := json.NewEncoder(w).Encode(payload)
+		if err == nil && gz != nil {
+			err = gz.Close()
+		}
+
+		// a nil error closes the pipe with io.EOF
+		_ = writer.CloseWithError(err)
+	}()
+
+	return reader
+}
+
+// progressWriter adds the bytes written through it to the progress.
+type progressWriter struct {
+	w        io.Writer
+	progress *progress.Manual
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	n, err := p.w.Write(b)
+	p.progress.Add(int64(n))
+
+	return n, err
+}
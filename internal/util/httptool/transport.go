@@ -0,0 +1,140 @@
+package httptool
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// TransportOptions are the settings of the connections to Carbon Black Cloud.
+type TransportOptions struct {
+	// ProxyURL is the proxy for all the requests, the HTTPS_PROXY & HTTP_PROXY env variables are used if empty
+	ProxyURL string `json:"proxy_url"`
+	// NoProxy is the comma-separated hosts to connect without the proxy, the NO_PROXY env variable is used if empty
+	NoProxy string `json:"no_proxy"`
+	// CABundle is a PEM file of the CA certificates trusted besides the system ones
+	CABundle string `json:"ca_bundle"`
+	// ClientCert and ClientKey are the PEM files of the client certificate for mutual TLS
+	ClientCert string `json:"client_cert"`
+	ClientKey  string `json:"client_key"`
+}
+
+// defaultTransport is used by the sessions created after it is set, nil means the http default transport.
+var (
+	defaultTransport        http.RoundTripper
+	defaultTransportOptions TransportOptions
+)
+
+// SetDefaultTransportOptions will build the transport used by the sessions created afterwards,
+// the default transport is kept if the options are invalid.
+func SetDefaultTransportOptions(opts TransportOptions) error {
+	defaultTransportOptions = opts
+
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	defaultTransport = transport
+
+	return nil
+}
+
+// DefaultTransportOptions returns the transport options last set, even if they are invalid.
+func DefaultTransportOptions() TransportOptions {
+	return defaultTransportOptions
+}
+
+// NewTransport will build a transport with the proxy, CA bundle and client certificate of the options.
+func NewTransport(opts TransportOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = opts.proxyFunc()
+
+	if opts.CABundle == "" && opts.ClientCert == "" && opts.ClientKey == "" {
+		return transport, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := ioutil.ReadFile(opts.CABundle)
+		if err != nil {
+			errMsg := fmt.Sprintf("Failed to read CA bundle %s", opts.CABundle)
+			return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+		}
+
+		if !pool.AppendCertsFromPEM(pem) {
+			errMsg := fmt.Sprintf("No PEM certificate found in CA bundle %s", opts.CABundle)
+			return nil, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCert != "" || opts.ClientKey != "" {
+		if opts.ClientCert == "" || opts.ClientKey == "" {
+			return nil, cberr.NewError(cberr.ConfigErr, "Both client certificate and client key are required", nil)
+		}
+
+		cert, err := tls.LoadX509KeyPair(opts.ClientCert, opts.ClientKey)
+		if err != nil {
+			errMsg := fmt.Sprintf("Failed to load client certificate %s", opts.ClientCert)
+			return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return transport, nil
+}
+
+// ProxyFor returns the proxy used for the target url, or an empty string for a direct connection.
+func (o TransportOptions) ProxyFor(target string) (string, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return "", err
+	}
+
+	proxyURL, err := o.proxyFunc()(&http.Request{URL: targetURL})
+	if err != nil || proxyURL == nil {
+		return "", err
+	}
+
+	return proxyURL.Redacted(), nil
+}
+
+func (o TransportOptions) proxyFunc() func(*http.Request) (*url.URL, error) {
+	if o.ProxyURL == "" && o.NoProxy == "" {
+		return http.ProxyFromEnvironment
+	}
+
+	proxyConfig := httpproxy.FromEnvironment()
+
+	if o.ProxyURL != "" {
+		proxyConfig.HTTPProxy = o.ProxyURL
+		proxyConfig.HTTPSProxy = o.ProxyURL
+	}
+
+	if o.NoProxy != "" {
+		proxyConfig.NoProxy = o.NoProxy
+	}
+
+	proxyForURL := proxyConfig.ProxyFunc()
+
+	return func(req *http.Request) (*url.URL, error) {
+		return proxyForURL(req.URL)
+	}
+}
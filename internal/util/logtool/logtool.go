@@ -0,0 +1,33 @@
+package logtool
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// The formats of the debug log.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Setup will send the logs to output in the format, with the secrets redacted.
+func Setup(output io.Writer, format string) error {
+	var formatter logrus.Formatter
+
+	switch format {
+	case FormatText, "":
+		formatter = &logrus.TextFormatter{}
+	case FormatJSON:
+		formatter = &logrus.JSONFormatter{}
+	default:
+		return fmt.Errorf("unsupported log format %q, must be text or json", format)
+	}
+
+	logrus.SetFormatter(&redactingFormatter{Formatter: formatter, redactor: defaultRedactor})
+	logrus.SetOutput(output)
+
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package logtool will setup the debug log: the redaction of the secrets, its format and its rotation.
+package logtool
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RedactedValue replaces the secrets in the logs and the error output.
+const RedactedValue = "[REDACTED]"
+
+// minSecretLength is the length under which a secret is not redacted, so that the logs stay readable.
+const minSecretLength = 4
+
+// defaultPatterns match the secrets by their context, the first group is kept and the rest is redacted.
+var defaultPatterns = []string{
+	`(?i)(x-auth-token["']?\s*[:=]\s*["']?)[^\s"',&]+`,
+	`(?i)(authorization["']?\s*[:=]\s*["']?(?:bearer|basic|token)\s+)[^\s"',&]+`,
+	`(?i)((?:password|passwd|secret|api[_-]?key|access[_-]?token)["']?\s*[:=]\s*["']?)[^\s"',&]+`,
+	`(--cb-api-key[=\s]+)\S+`,
+	`(--cred[=\s]+[^:\s]+:)\S+`,
+	`(://[^:/@\s]+:)[^@/\s]+(@)`,
+}
+
+var defaultRedactor = NewRedactor()
+
+// Redactor scrubs the registered secrets and the secrets matched by patterns from a text.
+type Redactor struct {
+	mu       sync.RWMutex
+	secrets  []string
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor creates a redactor with the default patterns.
+func NewRedactor() *Redactor {
+	r := &Redactor{}
+
+	for _, pattern := range defaultPatterns {
+		r.patterns = append(r.patterns, regexp.MustCompile(pattern))
+	}
+
+	return r
+}
+
+// AddSecret will register a secret to be redacted wherever it appears, the short ones are ignored.
+func (r *Redactor) AddSecret(secret string) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) < minSecretLength {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, s := range r.secrets {
+		if s == secret {
+			return
+		}
+	}
+
+	r.secrets = append(r.secrets, secret)
+}
+
+// AddPatterns will register regular expressions matching secrets, the first group of a pattern is kept if any.
+func (r *Redactor) AddPatterns(patterns ...string) error {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return err
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.patterns = append(r.patterns, compiled...)
+
+	return nil
+}
+
+// Redact returns the text with all the secrets replaced.
+func (r *Redactor) Redact(text string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, secret := range r.secrets {
+		text = strings.ReplaceAll(text, secret, RedactedValue)
+	}
+
+	for _, re := range r.patterns {
+		text = re.ReplaceAllStringFunc(text, func(match string) string {
+			groups := re.FindStringSubmatch(match)
+			if len(groups) < 2 { // nolint: gomnd
+				return RedactedValue
+			}
+
+			// keep the context around the secret
+			return groups[1] + RedactedValue + strings.Join(groups[2:], "")
+		})
+	}
+
+	return text
+}
+
+// AddSecret will register a secret to be redacted from the logs and the error output.
+func AddSecret(secret string) {
+	defaultRedactor.AddSecret(secret)
+}
+
+// AddPatterns will register regular expressions matching secrets in the logs and the error output.
+func AddPatterns(patterns ...string) error {
+	return defaultRedactor.AddPatterns(patterns...)
+}
+
+// Redact returns the text with all the registered secrets replaced.
+func Redact(text string) string {
+	return defaultRedactor.Redact(text)
+}
+
+// redactingFormatter scrubs the secrets from the log entries.
+type redactingFormatter struct {
+	logrus.Formatter
+	redactor *Redactor
+}
+
+// Format will redact the secrets from the message and the fields of the entry before it is formatted, as the
+// formatters escape the quotes in the secrets, then redact the secrets matched by the patterns in the formatted
+// entry, e.g. a field named password.
+func (f *redactingFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	// the entry is shared by the hooks, so a copy is redacted
+	redacted := *entry
+	redacted.Message = f.redactor.Redact(entry.Message)
+	redacted.Data = make(logrus.Fields, len(entry.Data))
+
+	for key, value := range entry.Data {
+		switch v := value.(type) {
+		case string:
+			value = f.redactor.Redact(v)
+		case error:
+			value = errors.New(f.redactor.Redact(v.Error()))
+		}
+
+		redacted.Data[key] = value
+	}
+
+	formatted, err := f.Formatter.Format(&redacted)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(f.redactor.Redact(string(formatted))), nil
+}
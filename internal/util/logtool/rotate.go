@@ -0,0 +1,108 @@
+package logtool
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+const logFileMode = 0666
+
+// RotatingFile is a log file rotated once it reaches its max size, keeping max backups of the previous ones,
+// named with the suffixes .1 to .N from the newest one.
+type RotatingFile struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       *os.File
+	size       int64
+}
+
+// OpenRotatingFile will open the log file in append mode, it is rotated first if it is already full;
+// a max size below 1 disables the rotation.
+func OpenRotatingFile(path string, maxSize int64, maxBackups int) (*RotatingFile, error) {
+	r := &RotatingFile{path: path, maxSize: maxSize, maxBackups: maxBackups}
+
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+
+	if r.full(0) {
+		if err := r.rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// Write will write to the log file, rotating it before if it would exceed its max size.
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.size > 0 && r.full(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+
+	return n, err
+}
+
+// Close will close the log file.
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.file.Close()
+}
+
+func (r *RotatingFile) full(incoming int64) bool {
+	return r.maxSize > 0 && r.size+incoming > r.maxSize
+}
+
+func (r *RotatingFile) open() error {
+	file, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFileMode)
+	if err != nil {
+		return err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	r.file, r.size = file, stat.Size()
+
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+
+	if r.maxBackups > 0 {
+		for i := r.maxBackups - 1; i > 0; i-- {
+			_ = os.Rename(backupName(r.path, i), backupName(r.path, i+1))
+		}
+
+		if err := os.Rename(r.path, backupName(r.path, 1)); err != nil {
+			return err
+		}
+	} else if err := os.Remove(r.path); err != nil {
+		return err
+	}
+
+	return r.open()
+}
+
+func backupName(path string, index int) string {
+	return fmt.Sprintf("%s.%d", path, index)
+}
@@ -6,9 +6,10 @@ import (
 	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
 )
 
-// ReadMemoryStats will print mem stat info in debug log.
+// ReadMemoryStats will print mem stat info in debug log, and record the peak memory in the metrics.
 func ReadMemoryStats(ctx context.Context) {
 	var (
 		maxRSSMB, totalRSSMB float64
@@ -26,6 +27,7 @@ func ReadMemoryStats(ctx context.Context) {
 
 		rssMB := float64(ms.Sys) / (1024 * 1024)
 		logrus.Debugf("Current memory usage: %.1fMB", rssMB)
+		metrictool.SetMax(metrictool.GaugePeakMemory, int64(ms.Sys))
 
 		if rssMB > maxRSSMB {
 			maxRSSMB = rssMB
@@ -35,6 +37,9 @@ func ReadMemoryStats(ctx context.Context) {
 		iterationsCount++
 	}
 
+	// short commands end before the first tick
+	track()
+
 trackLoop:
 	for {
 		select {
@@ -0,0 +1,190 @@
+// Package metrictool records the duration of the stages of a command and its resource usage.
+package metrictool
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+)
+
+// The names of the stages recorded during a scan.
+const (
+	StageImageIDLookup = "image_id_lookup"
+	StageCacheLookup   = "cache_lookup"
+	StagePull          = "pull"
+	StageCatalog       = "catalog"
+	StageCollectLayers = "collect_layers"
+	StageUpload        = "upload"
+	StageQueue         = "backend_queue"
+	StagePoll          = "backend_poll"
+)
+
+// The names of the counters recorded during a scan.
+const (
+	CounterCacheHits     = "cache_hits"
+	CounterBytesUploaded = "bytes_uploaded" // the bytes sent on the wire, after the compression
+	CounterFiles         = "files"
+	CounterPackages      = "packages"
+	CounterLayers        = "layers"
+	CounterPollRequests  = "poll_requests"
+	GaugePeakMemory      = "peak_memory_bytes"
+)
+
+// Span is a timed stage of the command.
+type Span struct {
+	Name       string            `json:"name"`
+	StartTime  time.Time         `json:"start"`
+	EndTime    time.Time         `json:"end"`
+	DurationMs int64             `json:"duration_ms"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Error      string            `json:"error,omitempty"`
+
+	id       string
+	recorder *recorder
+}
+
+// Report is the snapshot of all the metrics recorded for the command.
+type Report struct {
+	Command    string           `json:"command"`
+	StartTime  time.Time        `json:"start"`
+	EndTime    time.Time        `json:"end"`
+	DurationMs int64            `json:"duration_ms"`
+	Stages     []Span           `json:"stages"`
+	Counters   map[string]int64 `json:"counters"`
+
+	traceID string
+	spanID  string
+}
+
+type recorder struct {
+	mu       sync.Mutex
+	command  string
+	start    time.Time
+	traceID  string
+	spanID   string
+	spans    []*Span
+	counters map[string]int64
+}
+
+var defaultRecorder = newRecorder()
+
+func newRecorder() *recorder {
+	return &recorder{
+		start:    time.Now(),
+		traceID:  randomID(16), // nolint: gomnd
+		spanID:   randomID(8),  // nolint: gomnd
+		counters: make(map[string]int64),
+	}
+}
+
+// Start will reset the metrics, and record the following ones for the command.
+func Start(command string) {
+	r := newRecorder()
+	r.command = command
+
+	defaultRecorder = r
+}
+
+// StartSpan will start the timing of a stage, which lasts until End is called.
+func StartSpan(name string) *Span {
+	r := defaultRecorder
+
+	return &Span{Name: name, StartTime: time.Now(), id: randomID(8), recorder: r} // nolint: gomnd
+}
+
+// SetAttribute will attach a detail to the stage.
+func (s *Span) SetAttribute(key, value string) {
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]string)
+	}
+
+	s.Attributes[key] = value
+}
+
+// SetError will mark the stage as failed.
+func (s *Span) SetError(err error) {
+	if err != nil {
+		s.Error = err.Error()
+	}
+}
+
+// End will stop the timing of the stage and record it, only the first call is recorded.
+func (s *Span) End() {
+	if !s.EndTime.IsZero() {
+		return
+	}
+
+	s.EndTime = time.Now()
+	s.DurationMs = s.EndTime.Sub(s.StartTime).Milliseconds()
+
+	s.recorder.mu.Lock()
+	defer s.recorder.mu.Unlock()
+
+	s.recorder.spans = append(s.recorder.spans, s)
+}
+
+// Add will increase the counter by delta.
+func Add(name string, delta int64) {
+	r := defaultRecorder
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.counters[name] += delta
+}
+
+// SetMax will set the gauge to value if it is higher than the current one.
+func SetMax(name string, value int64) {
+	r := defaultRecorder
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if value > r.counters[name] {
+		r.counters[name] = value
+	}
+}
+
+// Snapshot will take the report of the metrics recorded so far, the stages are ordered by their start.
+func Snapshot() Report {
+	r := defaultRecorder
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	end := time.Now()
+	report := Report{
+		Command:    r.command,
+		StartTime:  r.start,
+		EndTime:    end,
+		DurationMs: end.Sub(r.start).Milliseconds(),
+		Stages:     make([]Span, 0, len(r.spans)),
+		Counters:   make(map[string]int64, len(r.counters)),
+		traceID:    r.traceID,
+		spanID:     r.spanID,
+	}
+
+	for _, s := range r.spans {
+		report.Stages = append(report.Stages, *s)
+	}
+
+	sort.SliceStable(report.Stages, func(i, j int) bool {
+		return report.Stages[i].StartTime.Before(report.Stages[j].StartTime)
+	})
+
+	for name, value := range r.counters {
+		report.Counters[name] = value
+	}
+
+	return report
+}
+
+// randomID will generate a random id of n bytes encoded in hex, as used for the trace and span ids.
+func randomID(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
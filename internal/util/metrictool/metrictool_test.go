@@ -0,0 +1,129 @@
+package metrictool_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
+)
+
+func recordScan() {
+	metrictool.Start("cbctl image scan")
+
+	pull := metrictool.StartSpan(metrictool.StagePull)
+	pull.End()
+	pull.End()
+
+	upload := metrictool.StartSpan(metrictool.StageUpload)
+	upload.SetAttribute("compressed", "true")
+	upload.SetError(errors.New("connection reset"))
+	upload.End()
+
+	metrictool.Add(metrictool.CounterPackages, 40)
+	metrictool.Add(metrictool.CounterPackages, 2)
+	metrictool.SetMax(metrictool.GaugePeakMemory, 2048)
+	metrictool.SetMax(metrictool.GaugePeakMemory, 1024)
+}
+
+func TestSnapshot(t *testing.T) {
+	recordScan()
+
+	report := metrictool.Snapshot()
+	require.Equal(t, "cbctl image scan", report.Command)
+	require.Len(t, report.Stages, 2)
+	require.Equal(t, metrictool.StagePull, report.Stages[0].Name)
+	require.Equal(t, metrictool.StageUpload, report.Stages[1].Name)
+	require.Equal(t, "connection reset", report.Stages[1].Error)
+	require.Equal(t, map[string]string{"compressed": "true"}, report.Stages[1].Attributes)
+	require.Equal(t, map[string]int64{
+		metrictool.CounterPackages: 42,
+		metrictool.GaugePeakMemory: 2048,
+	}, report.Counters)
+
+	// a new command starts from scratch
+	metrictool.Start("cbctl version")
+	require.Empty(t, metrictool.Snapshot().Stages)
+}
+
+func TestWriteFile(t *testing.T) {
+	recordScan()
+
+	report := metrictool.Snapshot()
+	dir := t.TempDir()
+
+	jsonFile := filepath.Join(dir, "metrics.json")
+	require.NoError(t, report.WriteFile(jsonFile, metrictool.FormatJSON))
+
+	data, err := ioutil.ReadFile(jsonFile)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, "cbctl image scan", decoded["command"])
+	require.Len(t, decoded["stages"], 2)
+
+	textFile := filepath.Join(dir, "metrics.txt")
+	require.NoError(t, report.WriteFile(textFile, metrictool.FormatOpenMetrics))
+
+	data, err = ioutil.ReadFile(textFile)
+	require.NoError(t, err)
+
+	text := string(data)
+	require.Contains(t, text, `cbctl_stage_duration_seconds{command="cbctl image scan",stage="pull"} `)
+	require.Contains(t, text, `cbctl_stage_duration_seconds{command="cbctl image scan",stage="upload"} `)
+	require.Contains(t, text, "# TYPE cbctl_packages gauge\n"+`cbctl_packages{command="cbctl image scan"} 42`)
+	require.Contains(t, text, `cbctl_peak_memory_bytes{command="cbctl image scan"} 2048`)
+	require.True(t, strings.HasSuffix(text, "# EOF\n"))
+
+	require.Error(t, report.WriteFile(textFile, "xml"))
+}
+
+func TestExportOTLP(t *testing.T) {
+	recordScan()
+
+	var received map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+
+		_ = json.NewDecoder(r.Body).Decode(&received)
+	}))
+	defer server.Close()
+
+	report := metrictool.Snapshot()
+	require.NoError(t, report.ExportOTLP(context.Background(), server.Client(), server.URL+"/"))
+
+	resourceSpans := received["resourceSpans"].([]interface{})
+	scopeSpans := resourceSpans[0].(map[string]interface{})["scopeSpans"].([]interface{})
+	spans := scopeSpans[0].(map[string]interface{})["spans"].([]interface{})
+	require.Len(t, spans, 3)
+
+	root := spans[0].(map[string]interface{})
+	upload := spans[2].(map[string]interface{})
+	require.Equal(t, "cbctl image scan", root["name"])
+	require.Len(t, root["traceId"], 32)
+	require.Equal(t, root["traceId"], upload["traceId"])
+	require.Equal(t, root["spanId"], upload["parentSpanId"])
+	require.Equal(t, "upload", upload["name"])
+	require.Equal(t, map[string]interface{}{"code": 2.0, "message": "connection reset"}, upload["status"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no tracing here", http.StatusNotFound)
+	}))
+	defer failing.Close()
+
+	err := report.ExportOTLP(context.Background(), failing.Client(), failing.URL)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no tracing here")
+}
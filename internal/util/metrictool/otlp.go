@@ -0,0 +1,158 @@
+package metrictool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// OTLPEndpointEnv is the standard env of the OpenTelemetry exporters for the OTLP endpoint.
+const OTLPEndpointEnv = "OTEL_EXPORTER_OTLP_ENDPOINT"
+
+const (
+	otlpTracesPath   = "/v1/traces"
+	otlpServiceName  = "cbctl"
+	otlpKindInternal = 1
+	otlpStatusError  = 2
+	maxErrorBody     = 512
+)
+
+type otlpValue struct {
+	StringValue string `json:"stringValue,omitempty"`
+	IntValue    string `json:"intValue,omitempty"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+// OTLPTraces will convert the report into an OTLP trace request in json, the command is the root span
+// and its stages are the child spans, the counters are attached to the root span.
+func (r Report) OTLPTraces() ([]byte, error) {
+	root := otlpSpan{
+		TraceID:           r.traceID,
+		SpanID:            r.spanID,
+		Name:              r.Command,
+		Kind:              otlpKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(r.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(r.EndTime.UnixNano(), 10),
+	}
+
+	for _, name := range sortedKeys(r.Counters) {
+		value := strconv.FormatInt(r.Counters[name], 10)
+		root.Attributes = append(root.Attributes, otlpAttribute{Key: "cbctl." + name, Value: otlpValue{IntValue: value}})
+	}
+
+	spans := []otlpSpan{root}
+
+	for _, s := range r.Stages {
+		span := otlpSpan{
+			TraceID:           r.traceID,
+			SpanID:            s.id,
+			ParentSpanID:      r.spanID,
+			Name:              s.Name,
+			Kind:              otlpKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+		}
+
+		for key, value := range s.Attributes {
+			span.Attributes = append(span.Attributes, otlpAttribute{Key: key, Value: otlpValue{StringValue: value}})
+		}
+
+		if s.Error != "" {
+			span.Status = otlpStatus{Code: otlpStatusError, Message: s.Error}
+		}
+
+		spans = append(spans, span)
+	}
+
+	request := otlpRequest{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpAttribute{
+			{Key: "service.name", Value: otlpValue{StringValue: otlpServiceName}},
+		}},
+		ScopeSpans: []otlpScopeSpans{{Scope: otlpScope{Name: otlpServiceName}, Spans: spans}},
+	}}}
+
+	return json.Marshal(request)
+}
+
+// ExportOTLP will send the report as spans to the OTLP/HTTP endpoint, e.g. http://localhost:4318.
+func (r Report) ExportOTLP(ctx context.Context, client *http.Client, endpoint string) error {
+	body, err := r.OTLPTraces()
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, otlpTracesPath) {
+		url += otlpTracesPath
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 { // nolint: gomnd
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("OTLP endpoint %s returned %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package metrictool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// The formats of the metrics file.
+const (
+	FormatJSON        = "json"
+	FormatOpenMetrics = "openmetrics"
+)
+
+const (
+	metricPrefix   = "cbctl_"
+	metricFileMode = 0600
+)
+
+// WriteFile will write the report to the file in the format.
+func (r Report) WriteFile(path, format string) error {
+	var write func(io.Writer) error
+
+	switch format {
+	case FormatJSON, "":
+		write = r.WriteJSON
+	case FormatOpenMetrics:
+		write = r.WriteOpenMetrics
+	default:
+		return fmt.Errorf("unsupported metrics format %q, must be json or openmetrics", format)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, metricFileMode)
+	if err != nil {
+		return err
+	}
+
+	if err := write(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// WriteJSON will write the report as an indented json document.
+func (r Report) WriteJSON(output io.Writer) error {
+	enc := json.NewEncoder(output)
+	enc.SetIndent("", " ")
+
+	return enc.Encode(r)
+}
+
+// WriteOpenMetrics will write the report in the OpenMetrics text format, the durations of the stages with
+// the same name are summed up.
+func (r Report) WriteOpenMetrics(output io.Writer) error {
+	var b strings.Builder
+
+	command := escapeLabel(r.Command)
+
+	b.WriteString("# TYPE cbctl_command_duration_seconds gauge\n")
+	b.WriteString("# UNIT cbctl_command_duration_seconds seconds\n")
+	fmt.Fprintf(&b, "cbctl_command_duration_seconds{command=\"%s\"} %s\n", command, seconds(r.DurationMs))
+
+	var stages []string
+
+	durations := make(map[string]int64)
+
+	for _, s := range r.Stages {
+		if _, ok := durations[s.Name]; !ok {
+			stages = append(stages, s.Name)
+		}
+
+		durations[s.Name] += s.DurationMs
+	}
+
+	if len(stages) > 0 {
+		b.WriteString("# TYPE cbctl_stage_duration_seconds gauge\n")
+		b.WriteString("# UNIT cbctl_stage_duration_seconds seconds\n")
+
+		for _, name := range stages {
+			fmt.Fprintf(&b, "cbctl_stage_duration_seconds{command=\"%s\",stage=\"%s\"} %s\n",
+				command, escapeLabel(name), seconds(durations[name]))
+		}
+	}
+
+	for _, name := range sortedKeys(r.Counters) {
+		metric := metricPrefix + name
+		fmt.Fprintf(&b, "# TYPE %s gauge\n", metric)
+		fmt.Fprintf(&b, "%s{command=\"%s\"} %d\n", metric, command, r.Counters[name])
+	}
+
+	b.WriteString("# EOF\n")
+
+	_, err := io.WriteString(output, b.String())
+
+	return err
+}
+
+// sortedKeys will return the names of the counters in order.
+func sortedKeys(counters map[string]int64) []string {
+	names := make([]string, 0, len(counters))
+	for name := range counters {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// seconds will format the milliseconds in seconds.
+func seconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000) // nolint: gomnd
+}
+
+// escapeLabel will escape the label value as required by the text format.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
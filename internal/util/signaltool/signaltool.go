@@ -0,0 +1,14 @@
+// Package signaltool provides utilities for handling the os signals
+package signaltool
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// InterruptContext returns a context cancelled on ^C or SIGTERM; stop resets the signal handling.
+func InterruptContext() (ctx context.Context, stop context.CancelFunc) {
+	return signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+}
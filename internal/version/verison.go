@@ -2,11 +2,13 @@
 package version
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"runtime"
 	"strings"
+	"time"
 
 	hashiVersion "github.com/hashicorp/go-version"
 	"github.com/sirupsen/logrus"
@@ -24,6 +26,9 @@ var (
 	platform    = fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
 )
 
+// fetchTimeout is how long the latest version is waited for, the check runs before every command.
+const fetchTimeout = 3 * time.Second
+
 // Version defines the application version details (generally from build information).
 type Version struct {
 	Version     string `json:"version"`     // application semantic version
@@ -47,12 +52,17 @@ func GetCurrentVersion() Version {
 }
 
 // IsUpdateAvailable indicates if there is a newer application version available, and if so, what the new version is.
+// Nothing is checked if the profile has no saas url or org key.
 func IsUpdateAvailable() (bool, string, error) {
 	currentVersionStr := GetCurrentVersion().Version
 	if currentVersionStr == "" {
 		return false, "", nil
 	}
 
+	if config.GetConfig(config.SaasURL) == "" || config.GetConfig(config.OrgKey) == "" {
+		return false, "", nil
+	}
+
 	currentVersion, err := hashiVersion.NewVersion(currentVersionStr)
 	if err != nil {
 		return false, "", fmt.Errorf("failed to parse current version: %w", err)
@@ -78,9 +88,14 @@ func fetchLatestVersion() (*hashiVersion.Version, error) {
 	basePath := fmt.Sprintf("%s/v1/orgs/%s", saasTmpl, config.GetConfig(config.OrgKey))
 	latestVersionFetchPath := fmt.Sprintf("%s/management/cli_instances/latest_version", basePath)
 
+	// a single short attempt, so that an unreachable backend does not stall the command
 	session := httptool.NewRequestSession(config.GetConfig(config.CBApiID), config.GetConfig(config.CBApiKey))
+	session.SetRetryPolicy(httptool.RetryPolicy{MaxAttempts: 1})
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
 
-	_, resp, err := session.RequestData(http.MethodGet, latestVersionFetchPath, nil)
+	_, resp, err := session.RequestDataWithContext(ctx, http.MethodGet, latestVersionFetchPath, nil)
 	if err != nil {
 		logrus.Errorf("Failed to fetch the latest version: %v", err)
 		return nil, err
@@ -0,0 +1,227 @@
+package attest_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/attest"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+const (
+	testFullTag = "docker.io/library/app:1.0"
+	testDigest  = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+)
+
+// writeKeys will write the private key and its public key to PEM files, and return their paths.
+func writeKeys(t *testing.T, key crypto.Signer, privateType string) (string, string) {
+	dir := t.TempDir()
+
+	var der []byte
+
+	var err error
+
+	if privateType == "EC PRIVATE KEY" {
+		der, err = x509.MarshalECPrivateKey(key.(*ecdsa.PrivateKey))
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+	}
+
+	require.NoError(t, err)
+
+	publicDer, err := x509.MarshalPKIXPublicKey(key.Public())
+	require.NoError(t, err)
+
+	privatePath := filepath.Join(dir, "key.pem")
+	publicPath := filepath.Join(dir, "key.pub")
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: privateType, Bytes: der})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})
+
+	require.NoError(t, ioutil.WriteFile(privatePath, privatePEM, 0o600))
+	require.NoError(t, ioutil.WriteFile(publicPath, publicPEM, 0o600))
+
+	return privatePath, publicPath
+}
+
+func testDocument() bom.JSONDocument {
+	return bom.JSONDocument{
+		Artifacts: []bom.JSONPackage{
+			{Name: "openssl", Version: "1.1.1k-r0", Type: "apk", PURL: "pkg:apk/alpine/openssl@1.1.1k-r0",
+				CPEs: []string{"cpe:2.3:a:openssl:openssl:1.1.1k-r0:*:*:*:*:*:*:*"}},
+			{Name: "requests", Version: "2.19.0", Type: "python", PURL: "pkg:pypi/requests@2.19.0"},
+		},
+		Source: bom.JSONSource{
+			Type:   "image",
+			Target: bom.JSONImageSource{ImageMetadata: source.ImageMetadata{UserInput: testFullTag}},
+		},
+		Distro: bom.JSONDistribution{Name: "alpine", Version: "3.13.5"},
+	}
+}
+
+func TestSignAndVerify(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		key         crypto.Signer
+		privateType string
+	}{
+		{ecKey, "EC PRIVATE KEY"},
+		{ecKey, "PRIVATE KEY"},
+		{edKey, "PRIVATE KEY"},
+	} {
+		privatePath, publicPath := writeKeys(t, tt.key, tt.privateType)
+
+		signer, err := attest.LoadSigner(privatePath)
+		require.NoError(t, err)
+
+		verifier, err := attest.LoadVerifier(publicPath)
+		require.NoError(t, err)
+		require.Equal(t, signer.KeyID(), verifier.KeyID())
+
+		statement, err := attest.SBOMStatement(testFullTag, testDigest, attest.FormatCycloneDX, testDocument())
+		require.NoError(t, err)
+
+		envelope, err := attest.Sign(statement, signer)
+		require.NoError(t, err)
+
+		verified, err := envelope.Verify(verifier)
+		require.NoError(t, err)
+		require.Equal(t, attest.PredicateCycloneDX, verified.PredicateType)
+		require.Equal(t, testDigest, verified.SubjectDigest())
+		require.Equal(t, testFullTag, verified.Subject[0].Name)
+
+		// the payload is tampered
+		payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+		require.NoError(t, err)
+
+		tampered := *envelope
+		tampered.Payload = base64.StdEncoding.EncodeToString(append(payload, ' '))
+
+		_, err = tampered.Verify(verifier)
+		require.Error(t, err)
+	}
+
+	// the envelope is not signed by the other key
+	ecPrivatePath, _ := writeKeys(t, ecKey, "PRIVATE KEY")
+	_, edPublicPath := writeKeys(t, edKey, "PRIVATE KEY")
+
+	signer, err := attest.LoadSigner(ecPrivatePath)
+	require.NoError(t, err)
+
+	verifier, err := attest.LoadVerifier(edPublicPath)
+	require.NoError(t, err)
+
+	statement, err := attest.NewStatement(testFullTag, testDigest, attest.PredicateScanResult, []byte(`{}`))
+	require.NoError(t, err)
+
+	envelope, err := attest.Sign(statement, signer)
+	require.NoError(t, err)
+
+	_, err = envelope.Verify(verifier)
+	require.Error(t, err)
+}
+
+func TestPAE(t *testing.T) {
+	require.Equal(t, "DSSEv1 29 http://example.com/HelloWorld 11 hello world",
+		string(attest.PAE("http://example.com/HelloWorld", []byte("hello world"))))
+}
+
+func TestSBOMPredicates(t *testing.T) {
+	for _, tt := range []struct {
+		format        string
+		predicateType string
+		field         string
+	}{
+		{attest.FormatCycloneDX, attest.PredicateCycloneDX, "components"},
+		{attest.FormatSPDX, attest.PredicateSPDX, "packages"},
+	} {
+		data, predicateType, err := attest.EncodeSBOM(testDocument(), tt.format)
+		require.NoError(t, err, tt.format)
+		require.Equal(t, tt.predicateType, predicateType)
+
+		var predicate map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(data, &predicate))
+
+		// the distro is also a component of the CycloneDX sbom
+		require.Contains(t, string(predicate[tt.field]), "pkg:apk/alpine/openssl@1.1.1k-r0", tt.format)
+		require.Contains(t, string(predicate[tt.field]), "pkg:pypi/requests@2.19.0", tt.format)
+	}
+
+	_, _, err := attest.EncodeSBOM(testDocument(), "syft")
+	require.Error(t, err)
+
+	_, err = attest.NewStatement(testFullTag, "latest", attest.PredicateScanResult, []byte(`{}`))
+	require.Error(t, err)
+}
+
+func TestVerifyFile(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	privatePath, publicPath := writeKeys(t, key, "PRIVATE KEY")
+
+	signer, err := attest.LoadSigner(privatePath)
+	require.NoError(t, err)
+
+	sbomStatement, err := attest.SBOMStatement(testFullTag, testDigest, attest.FormatSPDX, testDocument())
+	require.NoError(t, err)
+
+	resultStatement, err := attest.ScanResultStatement(&image.ScannedImage{
+		Identifier:      image.Identifier{FullTag: testFullTag, ManifestDigest: testDigest},
+		Vulnerabilities: []image.Vulnerability{{ID: "CVE-2021-3711", Name: "openssl", Severity: "CRITICAL"}},
+	})
+	require.NoError(t, err)
+
+	var envelopes []*attest.Envelope
+
+	for _, statement := range []*attest.Statement{sbomStatement, resultStatement} {
+		envelope, err := attest.Sign(statement, signer)
+		require.NoError(t, err)
+
+		envelopes = append(envelopes, envelope)
+	}
+
+	path := filepath.Join(t.TempDir(), "app.intoto.jsonl")
+	require.NoError(t, attest.WriteEnvelopes(path, envelopes))
+
+	// the private key file is accepted for its public key
+	for _, keyPath := range []string{publicPath, privatePath} {
+		verifier, err := attest.LoadVerifier(keyPath)
+		require.NoError(t, err)
+
+		report, err := attest.VerifyFile(path, verifier, testDigest)
+		require.NoError(t, err)
+		require.Len(t, report.Statements, 2)
+		require.Equal(t, attest.PredicateSPDX, report.Statements[0].PredicateType)
+		require.Equal(t, attest.PredicateScanResult, report.Statements[1].PredicateType)
+		require.Len(t, report.Rows(), 2)
+
+		_, err = attest.VerifyFile(path, verifier,
+			"sha256:2222222222222222222222222222222222222222222222222222222222222222")
+		require.Error(t, err)
+	}
+
+	notKey := filepath.Join(t.TempDir(), "key.pem")
+	require.NoError(t, ioutil.WriteFile(notKey, []byte("not a key"), 0o600))
+
+	_, err = attest.LoadSigner(notKey)
+	require.Error(t, err)
+}
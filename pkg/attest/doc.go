@@ -0,0 +1,3 @@
+// Package attest wraps the sbom and the scan result of an image into in-toto statements,
+// signed as DSSE envelopes with a local key, and verifies them.
+package attest
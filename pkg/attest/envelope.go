@@ -0,0 +1,152 @@
+package attest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+// PayloadType is the DSSE payload type of the in-toto statements.
+const PayloadType = "application/vnd.in-toto+json"
+
+const permModeReadWrite = 0600
+
+// Envelope is a DSSE envelope holding a signed statement.
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+// Signature is the signature of the envelope by a key.
+type Signature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// PAE is the pre-authentication encoding of the payload, which is what is actually signed.
+func PAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// Sign will sign the statement into an envelope.
+func Sign(statement *Statement, signer *Signer) (*Envelope, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, cberr.NewError(cberr.AttestationErr, "Failed to encode the in-toto statement", err)
+	}
+
+	sig, err := signer.Sign(PAE(PayloadType, payload))
+	if err != nil {
+		return nil, cberr.NewError(cberr.AttestationErr, "Failed to sign the in-toto statement", err)
+	}
+
+	return &Envelope{
+		PayloadType: PayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: signer.KeyID(), Sig: base64.StdEncoding.EncodeToString(sig)}},
+	}, nil
+}
+
+// Verify will check that the envelope is signed by the key and return its statement.
+func (e *Envelope) Verify(verifier *Verifier) (*Statement, error) {
+	if e.PayloadType != PayloadType {
+		return nil, fmt.Errorf("unsupported payload type %q", e.PayloadType)
+	}
+
+	payload, err := base64.StdEncoding.DecodeString(e.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	verified := false
+
+	for _, signature := range e.Signatures {
+		if signature.KeyID != "" && signature.KeyID != verifier.KeyID() {
+			continue
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err == nil && verifier.Verify(PAE(e.PayloadType, payload), sig) {
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		return nil, fmt.Errorf("no valid signature by key %s", verifier.KeyID())
+	}
+
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("invalid in-toto statement: %w", err)
+	}
+
+	if statement.Type != StatementType {
+		return nil, fmt.Errorf("unsupported statement type %q", statement.Type)
+	}
+
+	return &statement, nil
+}
+
+// WriteEnvelopes will write the envelopes to the file, one json envelope per line.
+func WriteEnvelopes(path string, envelopes []*Envelope) error {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	for _, envelope := range envelopes {
+		if err := enc.Encode(envelope); err != nil {
+			return cberr.NewError(cberr.AttestationErr, "Failed to encode the attestation", err)
+		}
+	}
+
+	if err := ioutil.WriteFile(path, buf.Bytes(), permModeReadWrite); err != nil {
+		errMsg := fmt.Sprintf("Failed to write attestation %s", path)
+		return cberr.NewError(cberr.AttestationErr, errMsg, err)
+	}
+
+	return nil
+}
+
+// ReadEnvelopes will read the envelopes of the file, either a single json envelope or one per line.
+func ReadEnvelopes(path string) ([]*Envelope, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to read attestation %s", path)
+		return nil, cberr.NewError(cberr.AttestationErr, errMsg, err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	var envelopes []*Envelope
+
+	dec := json.NewDecoder(file)
+
+	for {
+		var envelope Envelope
+		if err := dec.Decode(&envelope); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			errMsg := fmt.Sprintf("Failed to parse attestation %s", path)
+			return nil, cberr.NewError(cberr.AttestationErr, errMsg, err)
+		}
+
+		envelopes = append(envelopes, &envelope)
+	}
+
+	if len(envelopes) == 0 {
+		errMsg := fmt.Sprintf("No attestation found in %s", path)
+		return nil, cberr.NewError(cberr.AttestationErr, errMsg, nil)
+	}
+
+	return envelopes, nil
+}
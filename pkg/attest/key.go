@@ -0,0 +1,181 @@
+package attest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+// The PEM block types of the supported keys.
+const (
+	pemPrivateKey   = "PRIVATE KEY"
+	pemECPrivateKey = "EC PRIVATE KEY"
+	pemPublicKey    = "PUBLIC KEY"
+)
+
+// Signer signs the envelopes with a local ECDSA or ed25519 private key.
+type Signer struct {
+	key   crypto.Signer
+	keyID string
+}
+
+// Verifier verifies the envelopes with a local ECDSA or ed25519 public key.
+type Verifier struct {
+	key   crypto.PublicKey
+	keyID string
+}
+
+// LoadSigner will read the private key from the PEM file, either PKCS #8 or SEC 1 for ECDSA.
+func LoadSigner(path string) (*Signer, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, invalidKey(path, err)
+	}
+
+	keyID, err := publicKeyID(key.Public())
+	if err != nil {
+		return nil, invalidKey(path, err)
+	}
+
+	return &Signer{key: key, keyID: keyID}, nil
+}
+
+// LoadVerifier will read the public key from the PEM file, a private key file is accepted for its public key.
+func LoadVerifier(path string) (*Verifier, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var key crypto.PublicKey
+
+	if block.Type == pemPublicKey {
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	} else {
+		var private crypto.Signer
+		if private, err = parsePrivateKey(block); err == nil {
+			key = private.Public()
+		}
+	}
+
+	if err != nil {
+		return nil, invalidKey(path, err)
+	}
+
+	keyID, err := publicKeyID(key)
+	if err != nil {
+		return nil, invalidKey(path, err)
+	}
+
+	return &Verifier{key: key, keyID: keyID}, nil
+}
+
+// KeyID is the sha256 of the public key, it identifies the key in the signatures.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Sign will sign the data, the ECDSA signature is over the sha256 of the data in ASN.1 DER.
+func (s *Signer) Sign(data []byte) ([]byte, error) {
+	switch key := s.key.(type) {
+	case *ecdsa.PrivateKey:
+		hash := sha256.Sum256(data)
+		return ecdsa.SignASN1(rand.Reader, key, hash[:])
+	case ed25519.PrivateKey:
+		return ed25519.Sign(key, data), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", s.key)
+	}
+}
+
+// KeyID is the sha256 of the public key, it identifies the key in the signatures.
+func (v *Verifier) KeyID() string {
+	return v.keyID
+}
+
+// Verify reports if the signature of the data is made by the key.
+func (v *Verifier) Verify(data, signature []byte) bool {
+	switch key := v.key.(type) {
+	case *ecdsa.PublicKey:
+		hash := sha256.Sum256(data)
+		return ecdsa.VerifyASN1(key, hash[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, data, signature)
+	default:
+		return false
+	}
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, cberr.NewError(cberr.ConfigErr, fmt.Sprintf("Failed to read key %s", path), err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, invalidKey(path, errors.New("no PEM block found"))
+	}
+
+	return block, nil
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	switch block.Type {
+	case pemECPrivateKey:
+		return x509.ParseECPrivateKey(block.Bytes)
+	case pemPrivateKey:
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		switch key := key.(type) {
+		case *ecdsa.PrivateKey:
+			return key, nil
+		case ed25519.PrivateKey:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("unsupported key type %T, must be ECDSA or ed25519", key)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+}
+
+func publicKeyID(key crypto.PublicKey) (string, error) {
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+	default:
+		return "", fmt.Errorf("unsupported key type %T, must be ECDSA or ed25519", key)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(der)
+
+	return hex.EncodeToString(hash[:]), nil
+}
+
+func invalidKey(path string, err error) error {
+	errMsg := fmt.Sprintf("Invalid key %s, must be an ECDSA or ed25519 key in PEM", path)
+	return cberr.NewError(cberr.ConfigErr, errMsg, err)
+}
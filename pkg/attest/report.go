@@ -0,0 +1,92 @@
+package attest
+
+import (
+	"fmt"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+const (
+	predicateTypeHeader = "Predicate Type"
+	subjectHeader       = "Subject"
+	digestHeader        = "Digest"
+	keyIDHeader         = "Key ID"
+)
+
+// VerifiedStatement is a statement of the file whose signature is verified.
+type VerifiedStatement struct {
+	PredicateType string `json:"predicate_type"`
+	Subject       string `json:"subject"`
+	Digest        string `json:"digest"`
+	KeyID         string `json:"key_id"`
+}
+
+// Report is the result of the verification of an attestation file.
+type Report struct {
+	File       string              `json:"file"`
+	Statements []VerifiedStatement `json:"statements"`
+}
+
+// VerifyFile will verify every envelope of the file with the key, if the digest is set the subject of every
+// statement must be the image of this manifest digest.
+func VerifyFile(path string, verifier *Verifier, digest string) (*Report, error) {
+	envelopes, err := ReadEnvelopes(path)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{File: path}
+
+	for i, envelope := range envelopes {
+		statement, err := envelope.Verify(verifier)
+		if err != nil {
+			errMsg := fmt.Sprintf("Attestation #%d of %s is not verified", i+1, path)
+			return nil, cberr.NewError(cberr.AttestationErr, errMsg, err)
+		}
+
+		subjectDigest := statement.SubjectDigest()
+		if digest != "" && subjectDigest != digest {
+			errMsg := fmt.Sprintf("Attestation #%d of %s is about %s, not %s", i+1, path, subjectDigest, digest)
+			return nil, cberr.NewError(cberr.AttestationErr, errMsg, nil)
+		}
+
+		verifiedStatement := VerifiedStatement{
+			PredicateType: statement.PredicateType,
+			Digest:        subjectDigest,
+			KeyID:         verifier.KeyID(),
+		}
+		if len(statement.Subject) > 0 {
+			verifiedStatement.Subject = statement.Subject[0].Name
+		}
+
+		report.Statements = append(report.Statements, verifiedStatement)
+	}
+
+	return report, nil
+}
+
+// Title is the title of the report.
+func (r *Report) Title() string {
+	return fmt.Sprintf("Attestations verified in %s:", r.File)
+}
+
+// Footer summarizes the count of verified statements.
+func (r *Report) Footer() string {
+	return fmt.Sprintf("%d attestations verified\n", len(r.Statements))
+}
+
+// Header is the header columns of the report.
+func (r *Report) Header() []string {
+	return []string{predicateTypeHeader, subjectHeader, digestHeader, keyIDHeader}
+}
+
+// Rows returns all the verified statements as list of rows.
+func (r *Report) Rows() [][]string {
+	result := make([][]string, 0, len(r.Statements))
+
+	for _, statement := range r.Statements {
+		result = append(result, []string{statement.PredicateType, statement.Subject, statement.Digest, statement.KeyID})
+	}
+
+	return result
+}
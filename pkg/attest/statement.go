@@ -0,0 +1,123 @@
+package attest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anchore/syft/syft/formats"
+	"github.com/anchore/syft/syft/formats/cyclonedxjson"
+	"github.com/anchore/syft/syft/formats/spdxjson"
+	"github.com/anchore/syft/syft/sbom"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+// The in-toto statement and predicate types.
+const (
+	StatementType       = "https://in-toto.io/Statement/v0.1"
+	PredicateCycloneDX  = "https://cyclonedx.org/bom"
+	PredicateSPDX       = "https://spdx.dev/Document"
+	PredicateScanResult = "https://carbonblack.vmware.com/container/scan-result/v1"
+)
+
+// The formats of the sbom predicate.
+const (
+	FormatCycloneDX = "cyclonedx"
+	FormatSPDX      = "spdx"
+)
+
+// Subject is the artifact the statement is about, i.e. the image identified by its manifest digest.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Statement is an in-toto statement binding a predicate to the image.
+type Statement struct {
+	Type          string          `json:"_type"`
+	Subject       []Subject       `json:"subject"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+// NewStatement will create the statement of the predicate about the image, the manifest digest must be
+// a valid digest, e.g. sha256:<hex>.
+func NewStatement(fullTag, manifestDigest, predicateType string, predicate []byte) (*Statement, error) {
+	digest, err := godigest.Parse(manifestDigest)
+	if err != nil {
+		errMsg := fmt.Sprintf("Invalid manifest digest %q of %s for the attestation", manifestDigest, fullTag)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return &Statement{
+		Type: StatementType,
+		Subject: []Subject{{
+			Name:   fullTag,
+			Digest: map[string]string{digest.Algorithm().String(): digest.Encoded()},
+		}},
+		PredicateType: predicateType,
+		Predicate:     predicate,
+	}, nil
+}
+
+// SBOMStatement will create the statement of the sbom of the image, the predicate is the sbom encoded
+// in the given format.
+func SBOMStatement(fullTag, manifestDigest, format string, doc bom.JSONDocument) (*Statement, error) {
+	predicate, predicateType, err := EncodeSBOM(doc, format)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStatement(fullTag, manifestDigest, predicateType, predicate)
+}
+
+// ScanResultStatement will create the statement of the vulnerability result of the image.
+func ScanResultStatement(result *image.ScannedImage) (*Statement, error) {
+	predicate, err := json.Marshal(result)
+	if err != nil {
+		return nil, cberr.NewError(cberr.UnclassifiedErr, "Failed to encode the scan result for the attestation", err)
+	}
+
+	return NewStatement(result.FullTag, result.ManifestDigest, PredicateScanResult, predicate)
+}
+
+// EncodeSBOM will encode the sbom in the given format, cyclonedx or spdx json, and return its predicate type.
+func EncodeSBOM(doc bom.JSONDocument, format string) ([]byte, string, error) {
+	var sbomFormat sbom.Format
+
+	var predicateType string
+
+	switch strings.ToLower(format) {
+	case FormatCycloneDX:
+		sbomFormat, predicateType = cyclonedxjson.Format(), PredicateCycloneDX
+	case FormatSPDX:
+		sbomFormat, predicateType = spdxjson.Format2_3(), PredicateSPDX
+	default:
+		errMsg := fmt.Sprintf("Invalid sbom format %q, must be %s or %s", format, FormatCycloneDX, FormatSPDX)
+		return nil, "", cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	data, err := formats.Encode(doc.SyftSBOM(), sbomFormat)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to encode the sbom as %s", format)
+		return nil, "", cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+	}
+
+	return data, predicateType, nil
+}
+
+// SubjectDigest returns the digest of the first subject, e.g. sha256:<hex>.
+func (s *Statement) SubjectDigest() string {
+	if len(s.Subject) == 0 {
+		return ""
+	}
+
+	for algorithm, encoded := range s.Subject[0].Digest {
+		return algorithm + ":" + encoded
+	}
+
+	return ""
+}
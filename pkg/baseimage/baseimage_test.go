@@ -0,0 +1,85 @@
+package baseimage_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/baseimage"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
+)
+
+func TestCatalogLearnAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), baseimage.DefaultCatalogFileName)
+
+	catalog, err := baseimage.LoadCatalog(path)
+	require.NoError(t, err)
+	require.True(t, catalog.IsEmpty())
+
+	catalog.Learn("alpine:3.13", []string{"sha256:a"})
+	require.NoError(t, catalog.Save())
+
+	loaded, err := baseimage.LoadCatalog(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"alpine:3.13"}, loaded.Names())
+	require.Equal(t, []string{"sha256:a"}, loaded.BaseImages["alpine:3.13"])
+}
+
+func TestDetectLongestMatch(t *testing.T) {
+	catalog, err := baseimage.LoadCatalog(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+
+	catalog.Learn("alpine:3.13", []string{"sha256:a"})
+	catalog.Learn("python:3.9-alpine", []string{"sha256:a", "sha256:b"})
+	catalog.Learn("debian:11", []string{"sha256:x"})
+	catalog.Learn("too-long", []string{"sha256:a", "sha256:b", "sha256:c", "sha256:d"})
+
+	detected := catalog.Detect([]string{"sha256:a", "sha256:b", "sha256:c"})
+	require.NotNil(t, detected)
+	require.Equal(t, "python:3.9-alpine", detected.Name)
+	require.Equal(t, 2, detected.MatchedLayers)
+
+	require.Nil(t, catalog.Detect([]string{"sha256:b", "sha256:a"}))
+}
+
+func TestAnnotateSplitsVulnerabilities(t *testing.T) {
+	catalog, err := baseimage.LoadCatalog(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.NoError(t, err)
+
+	catalog.Learn("alpine:3.13", []string{"sha256:base"})
+
+	imgLayers := []layers.Layer{
+		{Digest: "sha256:base"},
+		{Digest: "sha256:empty", IsEmpty: true},
+		{Digest: "sha256:app"},
+	}
+
+	doc := bom.JSONDocument{
+		Artifacts: []bom.JSONPackage{
+			{Name: "openssl", Version: "1.1", Locations: []source.Location{locationIn("sha256:base")}},
+			{Name: "flask", Version: "2.0", Locations: []source.Location{locationIn("sha256:app")}},
+		},
+	}
+
+	scannedImage := &image.ScannedImage{
+		Vulnerabilities: []image.Vulnerability{
+			{ID: "CVE-1", Name: "openssl", Version: "1.1"},
+			{ID: "CVE-2", Name: "flask", Version: "2.0"},
+		},
+	}
+
+	catalog.Annotate(scannedImage, doc, imgLayers)
+
+	require.NotNil(t, scannedImage.BaseImage)
+	require.Equal(t, "alpine:3.13", scannedImage.BaseImage.Name)
+	require.Equal(t, image.OriginBase, scannedImage.Vulnerabilities[0].Origin)
+	require.Equal(t, image.OriginImage, scannedImage.Vulnerabilities[1].Origin)
+	require.Len(t, scannedImage.Header(), len(scannedImage.Rows()[0]))
+}
+
+func locationIn(layerID string) source.Location {
+	return source.NewLocationFromCoordinates(source.Coordinates{RealPath: "/lib", FileSystemID: layerID})
+}
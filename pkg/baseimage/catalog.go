@@ -0,0 +1,107 @@
+package baseimage
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultCatalogFileName is the name of the catalog file under the config home.
+	DefaultCatalogFileName = "base_images.yaml"
+
+	permModeReadWrite = 0600
+)
+
+// Catalog is the user-maintained list of known base images.
+//
+// It maps a base image name (e.g. alpine:3.13) to the ordered layer digests (diff ids) of that image.
+type Catalog struct {
+	BaseImages map[string][]string `json:"base_images"`
+
+	path string
+}
+
+// DefaultCatalogPath returns the path of the catalog file under the given config home.
+func DefaultCatalogPath(configHome string) string {
+	return filepath.Join(configHome, DefaultCatalogFileName)
+}
+
+// LoadCatalog will read the catalog from the given path, a missing file results in an empty catalog.
+func LoadCatalog(path string) (*Catalog, error) {
+	catalog := &Catalog{
+		BaseImages: make(map[string][]string),
+		path:       path,
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return catalog, nil
+		}
+
+		errMsg := fmt.Sprintf("Failed to read base image catalog %s", path)
+
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	if err := yaml.Unmarshal(content, catalog); err != nil {
+		errMsg := fmt.Sprintf("Failed to parse base image catalog %s", path)
+
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	if catalog.BaseImages == nil {
+		catalog.BaseImages = make(map[string][]string)
+	}
+
+	return catalog, nil
+}
+
+// Path returns the file path the catalog is loaded from.
+func (c *Catalog) Path() string {
+	return c.path
+}
+
+// IsEmpty returns true if there is no base image in the catalog.
+func (c *Catalog) IsEmpty() bool {
+	return len(c.BaseImages) == 0
+}
+
+// Names returns the sorted names of all the base images in the catalog.
+func (c *Catalog) Names() []string {
+	names := make([]string, 0, len(c.BaseImages))
+	for name := range c.BaseImages {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Learn will add or replace a base image with its layer digests.
+func (c *Catalog) Learn(name string, layerDigests []string) {
+	c.BaseImages[name] = layerDigests
+}
+
+// Save will persist the catalog to the path it was loaded from.
+func (c *Catalog) Save() error {
+	content, err := yaml.Marshal(c)
+	if err != nil {
+		return cberr.NewError(cberr.ConfigErr, "Failed to serialize base image catalog", err)
+	}
+
+	if err := ioutil.WriteFile(c.path, content, permModeReadWrite); err != nil {
+		errMsg := fmt.Sprintf("Failed to save base image catalog to %s", c.path)
+
+		return cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return nil
+}
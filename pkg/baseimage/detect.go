@@ -0,0 +1,109 @@
+package baseimage
+
+import (
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
+)
+
+// LayerDigests returns the digests of the non-empty layers in order.
+func LayerDigests(imgLayers []layers.Layer) []string {
+	digests := make([]string, 0, len(imgLayers))
+
+	for _, layer := range imgLayers {
+		if layer.IsEmpty {
+			continue
+		}
+
+		digests = append(digests, layer.Digest)
+	}
+
+	return digests
+}
+
+// Detect will match the leading layer digest chain against the catalog.
+//
+// A base image matches when all of its layers are the leading layers of the image,
+// the base image with the most layers wins. It returns nil if no base image matches.
+func (c *Catalog) Detect(layerDigests []string) *image.BaseImage {
+	var detected *image.BaseImage
+
+	for _, name := range c.Names() {
+		baseDigests := c.BaseImages[name]
+		if len(baseDigests) == 0 || len(baseDigests) > len(layerDigests) {
+			continue
+		}
+
+		if !hasPrefix(layerDigests, baseDigests) {
+			continue
+		}
+
+		if detected == nil || len(baseDigests) > detected.MatchedLayers {
+			detected = &image.BaseImage{Name: name, MatchedLayers: len(baseDigests)}
+		}
+	}
+
+	return detected
+}
+
+// Annotate will detect the base image for the scanned image and mark the origin of each vulnerability.
+//
+// A vulnerability is inherited from the base image if every location of its package is in a base layer.
+func (c *Catalog) Annotate(scannedImage *image.ScannedImage, doc bom.JSONDocument, imgLayers []layers.Layer) {
+	layerDigests := LayerDigests(imgLayers)
+
+	detected := c.Detect(layerDigests)
+	if detected == nil {
+		return
+	}
+
+	scannedImage.BaseImage = detected
+
+	baseLayers := make(map[string]bool, detected.MatchedLayers)
+	for _, digest := range layerDigests[:detected.MatchedLayers] {
+		baseLayers[digest] = true
+	}
+
+	basePackages := make(map[string]bool)
+
+	for _, artifact := range doc.Artifacts {
+		if len(artifact.Locations) == 0 {
+			continue
+		}
+
+		inBase := true
+
+		for _, location := range artifact.Locations {
+			if !baseLayers[location.FileSystemID] {
+				inBase = false
+				break
+			}
+		}
+
+		if inBase {
+			basePackages[packageKey(artifact.Name, artifact.Version)] = true
+		}
+	}
+
+	for i, vul := range scannedImage.Vulnerabilities {
+		if basePackages[packageKey(vul.Name, vul.Version)] {
+			scannedImage.Vulnerabilities[i].Origin = image.OriginBase
+		} else {
+			scannedImage.Vulnerabilities[i].Origin = image.OriginImage
+		}
+	}
+}
+
+func hasPrefix(digests, prefix []string) bool {
+	for i := range prefix {
+		if digests[i] != prefix[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func packageKey(name, version string) string {
+	return name + "@" + version
+}
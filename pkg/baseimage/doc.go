@@ -0,0 +1,2 @@
+// Package baseimage detects the base image of a scanned image from a local catalog of known base images
+package baseimage
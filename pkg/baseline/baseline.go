@@ -0,0 +1,195 @@
+package baseline
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+const permModeReadWrite = 0600
+
+// Finding is a vulnerability recorded in the baseline.
+type Finding struct {
+	ID      string `json:"id"`
+	Package string `json:"package_name"`
+	Version string `json:"package_version"`
+}
+
+// Baseline is the recorded findings of the image repos.
+//
+// It is keyed by repo instead of digest, so it follows the image across rebuilds.
+type Baseline struct {
+	Repos map[string][]Finding `json:"repos"`
+
+	path string
+}
+
+// Load will read the baseline from the given path, a missing file results in an empty baseline.
+func Load(path string) (*Baseline, error) {
+	baseline := &Baseline{
+		Repos: make(map[string][]Finding),
+		path:  path,
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return baseline, nil
+		}
+
+		errMsg := fmt.Sprintf("Failed to read baseline %s", path)
+
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	if err := json.Unmarshal(content, baseline); err != nil {
+		errMsg := fmt.Sprintf("Failed to parse baseline %s", path)
+
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	if baseline.Repos == nil {
+		baseline.Repos = make(map[string][]Finding)
+	}
+
+	return baseline, nil
+}
+
+// RepoKey returns the key of the image repo in the baseline, e.g. docker.io/library/alpine.
+func RepoKey(identifier image.Identifier) string {
+	if identifier.Registry == "" {
+		return identifier.Repo
+	}
+
+	return identifier.Registry + "/" + identifier.Repo
+}
+
+// Record will replace the findings of the repo of the scanned image with its current vulnerabilities.
+func (b *Baseline) Record(scannedImage *image.ScannedImage) {
+	findings := make([]Finding, 0, len(scannedImage.Vulnerabilities))
+	seen := make(map[Finding]bool, len(scannedImage.Vulnerabilities))
+
+	for _, vul := range scannedImage.Vulnerabilities {
+		finding := newFinding(vul)
+		if seen[finding] {
+			continue
+		}
+
+		seen[finding] = true
+		findings = append(findings, finding)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].ID != findings[j].ID {
+			return findings[i].ID < findings[j].ID
+		}
+
+		return findings[i].Package < findings[j].Package
+	})
+
+	b.Repos[RepoKey(scannedImage.Identifier)] = findings
+}
+
+// Apply will remove the vulnerabilities recorded in the baseline from the scanned image,
+// and attach the counts of baseline findings that still exist and that have been fixed.
+func (b *Baseline) Apply(scannedImage *image.ScannedImage) {
+	repo := RepoKey(scannedImage.Identifier)
+
+	recorded := make(map[Finding]bool, len(b.Repos[repo]))
+	for _, finding := range b.Repos[repo] {
+		recorded[finding] = true
+	}
+
+	newVulnerabilities := make([]image.Vulnerability, 0, len(scannedImage.Vulnerabilities))
+	existing := make(map[Finding]bool)
+
+	for _, vul := range scannedImage.Vulnerabilities {
+		finding := newFinding(vul)
+		if recorded[finding] {
+			existing[finding] = true
+			continue
+		}
+
+		newVulnerabilities = append(newVulnerabilities, vul)
+	}
+
+	scannedImage.Vulnerabilities = newVulnerabilities
+	scannedImage.Baseline = &image.BaselineSummary{
+		Repo:     repo,
+		Existing: len(existing),
+		Fixed:    len(recorded) - len(existing),
+	}
+}
+
+// ApplyViolations will remove the vulnerabilities recorded in the baseline from the policy violations of the image,
+// the violations left without any vulnerability are dropped, the ones not about vulnerabilities are kept.
+func (b *Baseline) ApplyViolations(
+	identifier image.Identifier, violations []image.PolicyViolation,
+) []image.PolicyViolation {
+	recorded := make(map[Finding]bool, len(b.Repos[RepoKey(identifier)]))
+	for _, finding := range b.Repos[RepoKey(identifier)] {
+		recorded[finding] = true
+	}
+
+	result := make([]image.PolicyViolation, 0, len(violations))
+
+	for _, violation := range violations {
+		violatedImages := make([]image.Violation, 0, len(violation.Violation.ViolatedImages))
+		hadVulnerabilities := false
+
+		for _, violated := range violation.Violation.ViolatedImages {
+			if len(violated.Vulnerabilities) == 0 {
+				violatedImages = append(violatedImages, violated)
+				continue
+			}
+
+			hadVulnerabilities = true
+			newVulnerabilities := make([]image.Vulnerability, 0, len(violated.Vulnerabilities))
+
+			for _, vul := range violated.Vulnerabilities {
+				if !recorded[newFinding(vul)] {
+					newVulnerabilities = append(newVulnerabilities, vul)
+				}
+			}
+
+			if len(newVulnerabilities) > 0 {
+				violated.Vulnerabilities = newVulnerabilities
+				violatedImages = append(violatedImages, violated)
+			}
+		}
+
+		if hadVulnerabilities && len(violatedImages) == 0 {
+			continue
+		}
+
+		violation.Violation.ViolatedImages = violatedImages
+		result = append(result, violation)
+	}
+
+	return result
+}
+
+// Save will persist the baseline to the path it was loaded from.
+func (b *Baseline) Save() error {
+	content, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return cberr.NewError(cberr.ConfigErr, "Failed to serialize baseline", err)
+	}
+
+	if err := ioutil.WriteFile(b.path, content, permModeReadWrite); err != nil {
+		errMsg := fmt.Sprintf("Failed to save baseline to %s", b.path)
+
+		return cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return nil
+}
+
+func newFinding(vul image.Vulnerability) Finding {
+	return Finding{ID: vul.ID, Package: vul.Name, Version: vul.Version}
+}
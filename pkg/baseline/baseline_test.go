@@ -0,0 +1,97 @@
+package baseline_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/baseline"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+func TestRecordAndApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+
+	recorded, err := baseline.Load(path)
+	require.NoError(t, err)
+
+	identifier := image.Identifier{Registry: "docker.io", Repo: "library/app", ManifestDigest: "sha256:v1"}
+	recorded.Record(&image.ScannedImage{
+		Identifier: identifier,
+		Vulnerabilities: []image.Vulnerability{
+			{ID: "CVE-1", Name: "openssl", Version: "1.1"},
+			{ID: "CVE-2", Name: "busybox", Version: "1.32"},
+		},
+	})
+	require.NoError(t, recorded.Save())
+
+	loaded, err := baseline.Load(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.Repos["docker.io/library/app"], 2)
+
+	// a rebuild of the same repo with a different digest
+	identifier.ManifestDigest = "sha256:v2"
+	rebuilt := &image.ScannedImage{
+		Identifier: identifier,
+		Vulnerabilities: []image.Vulnerability{
+			{ID: "CVE-1", Name: "openssl", Version: "1.1"},
+			{ID: "CVE-3", Name: "flask", Version: "2.0"},
+		},
+	}
+
+	loaded.Apply(rebuilt)
+
+	require.Len(t, rebuilt.Vulnerabilities, 1)
+	require.Equal(t, "CVE-3", rebuilt.Vulnerabilities[0].ID)
+	require.Equal(t, &image.BaselineSummary{Repo: "docker.io/library/app", Existing: 1, Fixed: 1}, rebuilt.Baseline)
+}
+
+func TestApplyOtherRepo(t *testing.T) {
+	recorded, err := baseline.Load(filepath.Join(t.TempDir(), "missing.json"))
+	require.NoError(t, err)
+
+	recorded.Record(&image.ScannedImage{
+		Identifier:      image.Identifier{Repo: "app"},
+		Vulnerabilities: []image.Vulnerability{{ID: "CVE-1", Name: "openssl", Version: "1.1"}},
+	})
+
+	other := &image.ScannedImage{
+		Identifier:      image.Identifier{Repo: "other"},
+		Vulnerabilities: []image.Vulnerability{{ID: "CVE-1", Name: "openssl", Version: "1.1"}},
+	}
+
+	recorded.Apply(other)
+
+	require.Len(t, other.Vulnerabilities, 1)
+	require.Equal(t, 0, other.Baseline.Fixed)
+}
+
+func TestApplyViolations(t *testing.T) {
+	recorded, err := baseline.Load(filepath.Join(t.TempDir(), "baseline.json"))
+	require.NoError(t, err)
+
+	identifier := image.Identifier{Repo: "app"}
+	recorded.Record(&image.ScannedImage{
+		Identifier:      identifier,
+		Vulnerabilities: []image.Vulnerability{{ID: "CVE-1", Name: "openssl", Version: "1.1"}},
+	})
+
+	violated := func(vulnerabilities ...image.Vulnerability) image.Violations {
+		return image.Violations{ViolatedImages: []image.Violation{{Image: "app", Vulnerabilities: vulnerabilities}}}
+	}
+
+	violations := recorded.ApplyViolations(identifier, []image.PolicyViolation{
+		{Rule: "accepted", Violation: violated(image.Vulnerability{ID: "CVE-1", Name: "openssl", Version: "1.1"})},
+		{Rule: "new", Violation: violated(
+			image.Vulnerability{ID: "CVE-1", Name: "openssl", Version: "1.1"},
+			image.Vulnerability{ID: "CVE-2", Name: "busybox", Version: "1.32"},
+		)},
+		{Rule: "root", Violation: violated()},
+	})
+
+	require.Len(t, violations, 2)
+	require.Equal(t, "new", violations[0].Rule)
+	require.Len(t, violations[0].Violation.ViolatedImages[0].Vulnerabilities, 1)
+	require.Equal(t, "CVE-2", violations[0].Violation.ViolatedImages[0].Vulnerabilities[0].ID)
+	require.Equal(t, "root", violations[1].Rule)
+}
@@ -0,0 +1,2 @@
+// Package baseline records the known vulnerabilities of an image repo, so only new findings are gated
+package baseline
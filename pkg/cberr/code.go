@@ -20,6 +20,10 @@ const (
 	DisplayErr
 	PolicyViolationErr
 	EmptyResponse
+	InterruptedErr
+	DiagnosticsFailedErr
+	AttestationErr
+	ReferrersErr
 )
 
 //nolint:gomnd
@@ -55,6 +59,14 @@ func (c Code) exitCode() int {
 		return 1
 	case EmptyResponse:
 		return 1
+	case InterruptedErr:
+		return 130
+	case DiagnosticsFailedErr:
+		return 1
+	case AttestationErr:
+		return 1
+	case ReferrersErr:
+		return 1
 	default:
 		return 0
 	}
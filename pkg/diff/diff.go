@@ -0,0 +1,273 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+// Change is the kind of a change between the two images.
+type Change string
+
+// All the kinds of changes.
+const (
+	Added     Change = "added"
+	Removed   Change = "removed"
+	Changed   Change = "changed"
+	Unchanged Change = "unchanged"
+)
+
+const (
+	changeHeader   = "Change"
+	kindHeader     = "Kind"
+	nameHeader     = "Name"
+	oldHeader      = "Old"
+	newHeader      = "New"
+	severityHeader = "Severity"
+
+	emptyCell = "-"
+)
+
+// PackageChange is a package version change between the two images.
+type PackageChange struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+	Change     Change `json:"change"`
+}
+
+// LayerChange is a layer added or removed between the two images.
+type LayerChange struct {
+	Digest  string `json:"digest"`
+	Command string `json:"command"`
+	Change  Change `json:"change"`
+}
+
+// Report is the difference between an old and a new scanned image.
+type Report struct {
+	Old                      image.Identifier      `json:"old"`
+	New                      image.Identifier      `json:"new"`
+	AddedVulnerabilities     []image.Vulnerability `json:"added_vulnerabilities"`
+	RemovedVulnerabilities   []image.Vulnerability `json:"removed_vulnerabilities"`
+	UnchangedVulnerabilities []image.Vulnerability `json:"unchanged_vulnerabilities"`
+	PackageChanges           []PackageChange       `json:"package_changes"`
+	LayerChanges             []LayerChange         `json:"layer_changes"`
+	// LayersAvailable is false if any of the two images has no layer data, e.g. loaded from an older report
+	LayersAvailable bool `json:"layers_available"`
+}
+
+// Compare will generate the difference between the old and the new scanned image.
+func Compare(oldImage, newImage *image.ScannedImage) *Report {
+	report := &Report{
+		Old:                      oldImage.Identifier,
+		New:                      newImage.Identifier,
+		AddedVulnerabilities:     make([]image.Vulnerability, 0),
+		RemovedVulnerabilities:   make([]image.Vulnerability, 0),
+		UnchangedVulnerabilities: make([]image.Vulnerability, 0),
+		PackageChanges:           comparePackages(oldImage, newImage),
+		LayerChanges:             make([]LayerChange, 0),
+		LayersAvailable:          len(oldImage.Layers) > 0 && len(newImage.Layers) > 0,
+	}
+
+	oldVulnerabilities := make(map[string]bool, len(oldImage.Vulnerabilities))
+	for _, v := range oldImage.Vulnerabilities {
+		oldVulnerabilities[VulnerabilityKey(v)] = true
+	}
+
+	newVulnerabilities := make(map[string]bool, len(newImage.Vulnerabilities))
+
+	for _, v := range newImage.Vulnerabilities {
+		newVulnerabilities[VulnerabilityKey(v)] = true
+
+		if oldVulnerabilities[VulnerabilityKey(v)] {
+			report.UnchangedVulnerabilities = append(report.UnchangedVulnerabilities, v)
+		} else {
+			report.AddedVulnerabilities = append(report.AddedVulnerabilities, v)
+		}
+	}
+
+	for _, v := range oldImage.Vulnerabilities {
+		if !newVulnerabilities[VulnerabilityKey(v)] {
+			report.RemovedVulnerabilities = append(report.RemovedVulnerabilities, v)
+		}
+	}
+
+	if report.LayersAvailable {
+		report.LayerChanges = compareLayers(oldImage.Layers, newImage.Layers)
+	}
+
+	return report
+}
+
+// NewVulnerabilitiesAtLeast returns the added vulnerabilities at least as severe as the threshold.
+func (r *Report) NewVulnerabilitiesAtLeast(threshold string) []image.Vulnerability {
+	result := make([]image.Vulnerability, 0)
+
+	for _, v := range r.AddedVulnerabilities {
+		if v.IsSeverityAtLeast(threshold) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+func comparePackages(oldImage, newImage *image.ScannedImage) []PackageChange {
+	collect := func(scannedImage *image.ScannedImage) map[string][]string {
+		versions := make(map[string][]string)
+		for _, artifact := range scannedImage.Packages.Artifacts {
+			key := artifact.Name + "|" + artifact.Type
+			versions[key] = append(versions[key], artifact.Version)
+		}
+
+		return versions
+	}
+
+	oldVersions, newVersions := collect(oldImage), collect(newImage)
+	changes := make([]PackageChange, 0)
+
+	for _, key := range sortedKeys(newVersions) {
+		name, packageType := splitKey(key)
+		newVersion := joinVersions(newVersions[key])
+
+		oldList, ok := oldVersions[key]
+		if !ok {
+			changes = append(changes, PackageChange{Name: name, Type: packageType, NewVersion: newVersion, Change: Added})
+			continue
+		}
+
+		if oldVersion := joinVersions(oldList); oldVersion != newVersion {
+			changes = append(changes, PackageChange{
+				Name: name, Type: packageType, OldVersion: oldVersion, NewVersion: newVersion, Change: Changed,
+			})
+		}
+	}
+
+	for _, key := range sortedKeys(oldVersions) {
+		if _, ok := newVersions[key]; ok {
+			continue
+		}
+
+		name, packageType := splitKey(key)
+		changes = append(changes, PackageChange{
+			Name: name, Type: packageType, OldVersion: joinVersions(oldVersions[key]), Change: Removed,
+		})
+	}
+
+	return changes
+}
+
+// compareLayers will compare the non-empty layers, empty layers have digests generated from the manifest.
+func compareLayers(oldLayers, newLayers []image.Layer) []LayerChange {
+	digests := func(imgLayers []image.Layer) map[string]bool {
+		result := make(map[string]bool, len(imgLayers))
+		for _, layer := range imgLayers {
+			if !layer.IsEmpty {
+				result[layer.Digest] = true
+			}
+		}
+
+		return result
+	}
+
+	oldDigests, newDigests := digests(oldLayers), digests(newLayers)
+	changes := make([]LayerChange, 0)
+
+	for _, layer := range oldLayers {
+		if !layer.IsEmpty && !newDigests[layer.Digest] {
+			changes = append(changes, LayerChange{Digest: layer.Digest, Command: layer.Command, Change: Removed})
+		}
+	}
+
+	for _, layer := range newLayers {
+		if !layer.IsEmpty && !oldDigests[layer.Digest] {
+			changes = append(changes, LayerChange{Digest: layer.Digest, Command: layer.Command, Change: Added})
+		}
+	}
+
+	return changes
+}
+
+func splitKey(key string) (string, string) {
+	parts := strings.SplitN(key, "|", 2) // nolint: gomnd
+	return parts[0], parts[1]
+}
+
+// Title is the title of the diff report.
+func (r *Report) Title() string {
+	return fmt.Sprintf("Difference between %s (%s) and %s (%s):",
+		r.Old.FullTag, r.Old.ManifestDigest, r.New.FullTag, r.New.ManifestDigest)
+}
+
+// Header is the header columns of the diff report.
+func (r *Report) Header() []string {
+	return []string{
+		changeHeader,
+		kindHeader,
+		nameHeader,
+		oldHeader,
+		newHeader,
+		severityHeader,
+	}
+}
+
+// Rows returns all the changes as list of rows, unchanged vulnerabilities are only counted in the footer.
+func (r *Report) Rows() [][]string {
+	result := make([][]string, 0)
+
+	for _, v := range r.AddedVulnerabilities {
+		result = append(result, []string{
+			string(Added), "vulnerability", v.ID, emptyCell, packageCell(v), v.GetSeverity(),
+		})
+	}
+
+	for _, v := range r.RemovedVulnerabilities {
+		result = append(result, []string{
+			string(Removed), "vulnerability", v.ID, packageCell(v), emptyCell, v.GetSeverity(),
+		})
+	}
+
+	for _, p := range r.PackageChanges {
+		result = append(result, []string{
+			string(p.Change), "package", p.Name, orEmpty(p.OldVersion), orEmpty(p.NewVersion), "",
+		})
+	}
+
+	for _, l := range r.LayerChanges {
+		oldCell, newCell := emptyCell, l.Command
+		if l.Change == Removed {
+			oldCell, newCell = l.Command, emptyCell
+		}
+
+		result = append(result, []string{string(l.Change), "layer", l.Digest, oldCell, newCell, ""})
+	}
+
+	return result
+}
+
+// Footer will summarize the counts of the changes.
+func (r *Report) Footer() string {
+	footer := fmt.Sprintf("Vulnerabilities: %d added, %d removed, %d unchanged; packages: %d changed",
+		len(r.AddedVulnerabilities), len(r.RemovedVulnerabilities), len(r.UnchangedVulnerabilities),
+		len(r.PackageChanges))
+
+	if r.LayersAvailable {
+		return footer + fmt.Sprintf("; layers: %d changed", len(r.LayerChanges))
+	}
+
+	return footer + "; layers: unavailable"
+}
+
+func packageCell(v image.Vulnerability) string {
+	return fmt.Sprintf("%s %s", v.Name, v.Version)
+}
+
+func orEmpty(value string) string {
+	if value == "" {
+		return emptyCell
+	}
+
+	return value
+}
@@ -0,0 +1,83 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/diff"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+func TestCompare(t *testing.T) {
+	oldImage := &image.ScannedImage{
+		Vulnerabilities: []image.Vulnerability{
+			{ID: "CVE-1", Name: "openssl", Version: "1.1", Severity: "HIGH"},
+			{ID: "CVE-2", Name: "busybox", Version: "1.32", Severity: "LOW"},
+		},
+		Packages: bom.JSONDocument{Artifacts: []bom.JSONPackage{
+			{Name: "openssl", Version: "1.1", Type: "apk"},
+			{Name: "busybox", Version: "1.32", Type: "apk"},
+		}},
+		Layers: []image.Layer{{Digest: "sha256:base"}, {Digest: "sha256:old"}},
+	}
+	newImage := &image.ScannedImage{
+		Vulnerabilities: []image.Vulnerability{
+			{ID: "CVE-1", Name: "openssl", Version: "1.2", Severity: "HIGH"},
+			{ID: "CVE-3", Name: "flask", Version: "2.0", Severity: "CRITICAL"},
+		},
+		Packages: bom.JSONDocument{Artifacts: []bom.JSONPackage{
+			{Name: "openssl", Version: "1.2", Type: "apk"},
+			{Name: "flask", Version: "2.0", Type: "python"},
+		}},
+		Layers: []image.Layer{{Digest: "sha256:base"}, {Digest: "sha256:empty", IsEmpty: true}, {Digest: "sha256:new"}},
+	}
+
+	report := diff.Compare(oldImage, newImage)
+
+	require.Len(t, report.AddedVulnerabilities, 1)
+	require.Equal(t, "CVE-3", report.AddedVulnerabilities[0].ID)
+	require.Len(t, report.RemovedVulnerabilities, 1)
+	require.Equal(t, "CVE-2", report.RemovedVulnerabilities[0].ID)
+	require.Len(t, report.UnchangedVulnerabilities, 1)
+
+	require.Equal(t, []diff.PackageChange{
+		{Name: "flask", Type: "python", NewVersion: "2.0", Change: diff.Added},
+		{Name: "openssl", Type: "apk", OldVersion: "1.1", NewVersion: "1.2", Change: diff.Changed},
+		{Name: "busybox", Type: "apk", OldVersion: "1.32", Change: diff.Removed},
+	}, report.PackageChanges)
+
+	require.True(t, report.LayersAvailable)
+	require.Equal(t, []diff.LayerChange{
+		{Digest: "sha256:old", Change: diff.Removed},
+		{Digest: "sha256:new", Change: diff.Added},
+	}, report.LayerChanges)
+
+	require.Len(t, report.NewVulnerabilitiesAtLeast("CRITICAL"), 1)
+	require.Len(t, report.NewVulnerabilitiesAtLeast("HIGH"), 1)
+	require.Len(t, report.Rows(), 7)
+
+	for _, row := range report.Rows() {
+		require.Len(t, row, len(report.Header()))
+	}
+}
+
+func TestLoadReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+
+	content, err := json.Marshal(image.ScannedImage{
+		Vulnerabilities: []image.Vulnerability{{ID: "CVE-1", Name: "openssl"}},
+	})
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, append([]byte("\n  "), content...), 0600))
+
+	require.True(t, diff.IsReportFile(path))
+	require.False(t, diff.IsReportFile("alpine:3.13"))
+
+	scannedImage, err := diff.LoadReport(path)
+	require.NoError(t, err)
+	require.Equal(t, "CVE-1", scannedImage.Vulnerabilities[0].ID)
+}
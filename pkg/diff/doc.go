@@ -0,0 +1,2 @@
+// Package diff compares the vulnerabilities, packages and layers of two scanned images
+package diff
@@ -0,0 +1,83 @@
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+// IsReportFile returns true if the input is a file holding a JSON document, i.e. a saved scan report.
+func IsReportFile(input string) bool {
+	file, err := os.Open(input)
+	if err != nil {
+		return false
+	}
+
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := bufio.NewReader(file)
+
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return false
+		}
+
+		if !isSpace(b) {
+			return b == '{'
+		}
+	}
+}
+
+// LoadReport will read a scan report previously saved with the json output.
+func LoadReport(path string) (*image.ScannedImage, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to read scan report %s", path)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	var scannedImage image.ScannedImage
+
+	if err := json.Unmarshal(bytes.TrimSpace(content), &scannedImage); err != nil {
+		errMsg := fmt.Sprintf("Failed to parse scan report %s", path)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return &scannedImage, nil
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\r' || b == '\t'
+}
+
+// VulnerabilityKey is the key identifying the same vulnerability across two images.
+func VulnerabilityKey(v image.Vulnerability) string {
+	return v.ID + "|" + v.Name
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func joinVersions(versions []string) string {
+	sort.Strings(versions)
+	return strings.Join(versions, ", ")
+}
@@ -0,0 +1,155 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+// The services of the backend.
+const (
+	ServiceAnalyzer   = "analyzer"
+	ServiceGuardrails = "guardrails"
+	ServiceManagement = "management"
+)
+
+// AccessGranted is the message of a service the api key was verified to access.
+const AccessGranted = "Access granted"
+
+const (
+	credentialsCheck = "credentials"
+
+	analyzerHealthTemplate = "%s/v1beta/orgs/%s/analyzer/health"
+	latestVersionTemplate  = "%s/v1/orgs/%s/management/cli_instances/latest_version"
+	// guardrails has no health endpoint, the validation of an unknown image stands for it
+	guardrailsProbeTemplate = "%s/v1/orgs/%s/guardrails/validator/build/image/sha256:%064d"
+
+	// slowLatency is the latency above which a service is reported as slow
+	slowLatency = 2 * time.Second
+)
+
+// CheckCredentials will check the api id and key with an authenticated request to the url.
+func CheckCredentials(ctx context.Context, session *httptool.RequestSession, apiID, apiKey, url string) Result {
+	result := Result{Name: credentialsCheck}
+
+	if apiID == "" || apiKey == "" {
+		result.Status = StatusFail
+		result.Message = "No api id or api key configured"
+		result.Hint = `set them with "cbctl auth set <api_id> <api_secret_key>"`
+
+		return result
+	}
+
+	_, _, err := session.RequestDataWithContext(ctx, http.MethodGet, url, nil)
+
+	switch {
+	case err == nil:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Authenticated as %s", apiID)
+	case cberr.ErrorCode(err) == cberr.HTTPNotAllowedErr:
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Credentials of %s rejected", apiID)
+		result.Hint = "check the api key has not expired and has the container image permissions"
+	case cberr.ErrorCode(err) == cberr.HTTPConnectionErr:
+		result.Status = StatusFail
+		result.Message = "Failed to reach the backend to verify the credentials"
+		result.Hint = "check the saas_url and the transport settings"
+	default:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Credentials not verified: %s", cberr.ErrorMessage(err))
+	}
+
+	return result
+}
+
+// CheckEndpoint will check that a service of the backend is reachable and report its latency,
+// any response of the service counts as reachable.
+func CheckEndpoint(ctx context.Context, session *httptool.RequestSession, name, url string) Result {
+	result := Result{Name: name}
+
+	start := time.Now()
+	_, _, err := session.RequestDataWithContext(ctx, http.MethodGet, url, nil)
+	latency := time.Since(start).Round(time.Millisecond)
+
+	if cberr.ErrorCode(err) == cberr.HTTPConnectionErr || cberr.ErrorCode(err) == cberr.InterruptedErr {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Unreachable: %s", cberr.ErrorMessage(err))
+		result.Hint = "check the saas_url, the network and the transport settings"
+
+		return result
+	}
+
+	if latency > slowLatency {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Reachable but slow, latency %v", latency)
+		result.Hint = "scans may time out, consider raising the --timeout of the scan"
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Reachable, latency %v", latency)
+
+	return result
+}
+
+// Service is a service of the backend with an authenticated endpoint of the org.
+type Service struct {
+	Name string
+	URL  string
+}
+
+// Services returns the services of the backend with an authenticated endpoint of the org each.
+func Services(saasURL, orgKey string) []Service {
+	baseURL := TrimSaasURL(saasURL)
+
+	return []Service{
+		{Name: ServiceManagement, URL: fmt.Sprintf(latestVersionTemplate, baseURL, orgKey)},
+		{Name: ServiceAnalyzer, URL: fmt.Sprintf(analyzerHealthTemplate, baseURL, orgKey)},
+		{Name: ServiceGuardrails, URL: fmt.Sprintf(guardrailsProbeTemplate, baseURL, orgKey, 0)},
+	}
+}
+
+// TrimSaasURL will remove the api version and path suffixes of the saas url, as found in the older configs.
+func TrimSaasURL(saasURL string) string {
+	saasURL = strings.Trim(saasURL, "/")
+	saasURL = strings.TrimSuffix(saasURL, "/orgs")
+	saasURL = strings.TrimSuffix(saasURL, "/v1")
+
+	return strings.TrimSuffix(saasURL, "/v1beta")
+}
+
+// CheckAccess will check that the api key can access a service of the backend with an authenticated request,
+// a not found response is reachable but does not verify the access.
+func CheckAccess(ctx context.Context, session *httptool.RequestSession, service Service) Result {
+	result := Result{Name: service.Name}
+
+	_, _, err := session.RequestDataWithContext(ctx, http.MethodGet, service.URL, nil)
+
+	switch code := cberr.ErrorCode(err); {
+	case err == nil:
+		result.Status = StatusPass
+		result.Message = AccessGranted
+	case code == cberr.HTTPNotFoundErr:
+		result.Status = StatusPass
+		result.Message = "Reachable, access unverified"
+	case code == cberr.HTTPNotAllowedErr:
+		result.Status = StatusFail
+		result.Message = "Access denied"
+		result.Hint = fmt.Sprintf("grant the api key the permissions of the %s service", service.Name)
+	case code == cberr.HTTPConnectionErr || code == cberr.InterruptedErr:
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Unreachable: %s", cberr.ErrorMessage(err))
+		result.Hint = "check the saas_url, the network and the transport settings"
+	default:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Access not verified: %s", cberr.ErrorMessage(err))
+	}
+
+	return result
+}
@@ -0,0 +1,104 @@
+package doctor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/config"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/zalando/go-keyring"
+)
+
+const (
+	configCheck  = "config"
+	profileCheck = "profile"
+	keyringCheck = "keyring"
+
+	cbAPIIDKey = "cb_api_id"
+)
+
+// CheckConfig will check that the config file, if any, can be parsed and is valid.
+func CheckConfig(configFile string) Result {
+	result := Result{Name: configCheck}
+
+	if _, err := os.Stat(configFile); configFile == "" || os.IsNotExist(err) {
+		result.Status = StatusWarn
+		result.Message = "No config file found, only the flags and env variables are used"
+		result.Hint = `create one with "cbctl config"`
+
+		return result
+	}
+
+	settings, err := config.ReadConfigFile(configFile)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = err.Error()
+		result.Hint = "fix the syntax of the config file or upgrade cbctl"
+
+		return result
+	}
+
+	if err := config.ValidateSettings(settings); err != nil {
+		result.Status = StatusFail
+		result.Message = cberr.ErrorMessage(err)
+		result.Hint = `fix the listed options with "cbctl config <option> <value>"`
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Parsed %s, schema version %d", configFile, config.SchemaVersion)
+
+	return result
+}
+
+// CheckProfile will check that the active profile has the settings needed to reach the backend.
+func CheckProfile(profile, saasURL, orgKey string) Result {
+	result := Result{Name: profileCheck}
+
+	var missing []string
+
+	if saasURL == "" {
+		missing = append(missing, "saas_url")
+	}
+
+	if orgKey == "" {
+		missing = append(missing, "org_key")
+	}
+
+	if len(missing) > 0 {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Profile %s has no %v", profile, missing)
+		result.Hint = `set the missing options with "cbctl config <option> <value>"`
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Profile %s: %s, org %s", profile, saasURL, orgKey)
+
+	return result
+}
+
+// CheckKeyring will check that the credential store of the system can be accessed.
+func CheckKeyring(profile string) Result {
+	result := Result{Name: keyringCheck}
+
+	_, err := keyring.Get(profile, cbAPIIDKey)
+
+	switch err {
+	case nil:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Credentials of %s found in the keyring", profile)
+	case keyring.ErrNotFound:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Keyring accessible, no credentials of %s stored", profile)
+	default:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Keyring not accessible: %v", err)
+		result.Hint = fmt.Sprintf("the credentials will be stored in an encrypted file with the passphrase of %s, "+
+			"or in plain text in the config file", config.PassphraseEnv)
+	}
+
+	return result
+}
@@ -0,0 +1,85 @@
+package doctor
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	daemonCheck = "daemon"
+
+	dialTimeout = 2 * time.Second
+)
+
+// DaemonHosts returns the docker and podman daemon hosts to try, the env variables take precedence.
+func DaemonHosts() []string {
+	var hosts []string
+
+	for _, env := range []string{"DOCKER_HOST", "CONTAINER_HOST"} {
+		if host := os.Getenv(env); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+
+	hosts = append(hosts, "unix:///var/run/docker.sock", "unix:///run/podman/podman.sock")
+
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		hosts = append(hosts, "unix://"+filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+
+	return hosts
+}
+
+// CheckDaemonSocket will check that one of the daemon hosts accepts connections.
+func CheckDaemonSocket(hosts []string) Result {
+	result := Result{Name: daemonCheck}
+
+	var failures []string
+
+	for _, host := range hosts {
+		if err := dialHost(host); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", host, err))
+			continue
+		}
+
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Connected to %s", host)
+
+		return result
+	}
+
+	result.Status = StatusWarn
+	result.Message = fmt.Sprintf("No docker or podman daemon reachable (%s)", strings.Join(failures, "; "))
+	result.Hint = "start the daemon or set DOCKER_HOST, images can still be pulled from the registries"
+
+	return result
+}
+
+func dialHost(host string) error {
+	hostURL, err := url.Parse(host)
+	if err != nil {
+		return err
+	}
+
+	var conn net.Conn
+
+	switch hostURL.Scheme {
+	case "unix":
+		conn, err = net.DialTimeout("unix", hostURL.Path, dialTimeout)
+	case "tcp":
+		conn, err = net.DialTimeout("tcp", hostURL.Host, dialTimeout)
+	default:
+		return fmt.Errorf("unsupported scheme %q", hostURL.Scheme)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
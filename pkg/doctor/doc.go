@@ -0,0 +1,2 @@
+// Package doctor runs the diagnostics of the environment of the cli tool and reports the result of each check
+package doctor
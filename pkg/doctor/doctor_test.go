@@ -0,0 +1,142 @@
+package doctor_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/doctor"
+)
+
+func TestCheckTransport(t *testing.T) {
+	opts := httptool.TransportOptions{ProxyURL: "http://proxy.example.com:3128", NoProxy: "localhost"}
+
+	result := doctor.CheckTransport(opts, "https://defense.conferdeploy.net")
+	require.Equal(t, doctor.StatusPass, result.Status)
+	require.Contains(t, result.Message, "proxy: http://proxy.example.com:3128")
+	require.Contains(t, result.Message, "no_proxy: localhost")
+
+	opts.CABundle = filepath.Join(t.TempDir(), "missing.pem")
+	result = doctor.CheckTransport(opts, "https://defense.conferdeploy.net")
+	require.Equal(t, doctor.StatusFail, result.Status)
+	require.NotEmpty(t, result.Hint)
+
+	report := &doctor.Report{}
+	report.Add(result)
+	require.Equal(t, 1, report.Count(doctor.StatusFail))
+	require.Equal(t, "0 passed, 0 warnings, 1 failed\n", report.Footer())
+}
+
+func TestCheckConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	require.Equal(t, doctor.StatusWarn, doctor.CheckConfig("").Status)
+	require.Equal(t, doctor.StatusWarn, doctor.CheckConfig(filepath.Join(dir, "missing.yaml")).Status)
+	valid := filepath.Join(dir, "valid.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte("default:\n  saas_url: https://example.com\n  org_key: ORG\n"), 0600))
+	require.Equal(t, doctor.StatusPass, doctor.CheckConfig(valid).Status)
+
+	noOrgKey := filepath.Join(dir, "no-org-key.yaml")
+	require.NoError(t, os.WriteFile(noOrgKey, []byte("default:\n  saas_url: https://example.com\n"), 0600))
+	require.Equal(t, doctor.StatusFail, doctor.CheckConfig(noOrgKey).Status)
+
+	invalid := filepath.Join(dir, "invalid.yaml")
+	require.NoError(t, os.WriteFile(invalid, []byte("default:\n  saas_url: [\n"), 0600))
+	require.Equal(t, doctor.StatusFail, doctor.CheckConfig(invalid).Status)
+
+	require.Equal(t, doctor.StatusPass, doctor.CheckProfile("default", "https://example.com", "ORG").Status)
+
+	result := doctor.CheckProfile("default", "", "ORG")
+	require.Equal(t, doctor.StatusFail, result.Status)
+	require.Contains(t, result.Message, "saas_url")
+}
+
+func TestCheckBackend(t *testing.T) {
+	handler := http.NewServeMux()
+	handler.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("OK"))
+	})
+	handler.HandleFunc("/restricted", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	mockServer := httptest.NewServer(handler)
+	defer mockServer.Close()
+
+	ctx := context.Background()
+	session := httptool.NewRequestSession("id", "key")
+	session.SetRetryPolicy(httptool.RetryPolicy{MaxAttempts: 1})
+
+	require.Equal(t, doctor.StatusPass,
+		doctor.CheckCredentials(ctx, session, "id", "key", mockServer.URL+"/health").Status)
+	require.Equal(t, doctor.StatusFail,
+		doctor.CheckCredentials(ctx, session, "id", "key", mockServer.URL+"/restricted").Status)
+	require.Equal(t, doctor.StatusFail,
+		doctor.CheckCredentials(ctx, session, "", "", mockServer.URL+"/health").Status)
+
+	// the access of the api key to a service is only granted by a successful response
+	granted := doctor.CheckAccess(ctx, session, doctor.Service{Name: "analyzer", URL: mockServer.URL + "/health"})
+	require.Equal(t, doctor.StatusPass, granted.Status)
+
+	denied := doctor.CheckAccess(ctx, session, doctor.Service{Name: "analyzer", URL: mockServer.URL + "/restricted"})
+	require.Equal(t, doctor.StatusFail, denied.Status)
+	require.Equal(t, "Access denied", denied.Message)
+
+	unverified := doctor.CheckAccess(ctx, session, doctor.Service{Name: "guardrails", URL: mockServer.URL + "/missing"})
+	require.Equal(t, doctor.StatusPass, unverified.Status)
+	require.Equal(t, "Reachable, access unverified", unverified.Message)
+
+	services := doctor.Services("https://example.com/v1/orgs/", "ORG")
+	require.Len(t, services, 3)
+	require.Equal(t, "https://example.com/v1beta/orgs/ORG/analyzer/health", services[1].URL)
+	require.Equal(t, "https://example.com/v1/orgs/ORG/guardrails/validator/build/image/sha256:"+strings.Repeat("0", 64),
+		services[2].URL)
+
+	// any response counts as reachable
+	require.Equal(t, doctor.StatusPass, doctor.CheckEndpoint(ctx, session, "analyzer", mockServer.URL+"/health").Status)
+	require.Equal(t, doctor.StatusPass, doctor.CheckEndpoint(ctx, session, "analyzer", mockServer.URL+"/missing").Status)
+
+	unreachable := mockServer.URL
+	mockServer.Close()
+	require.Equal(t, doctor.StatusFail, doctor.CheckEndpoint(ctx, session, "analyzer", unreachable+"/health").Status)
+}
+
+func TestCheckDaemonSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "docker.sock")
+
+	listener, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	missing := "unix://" + filepath.Join(t.TempDir(), "missing.sock")
+
+	result := doctor.CheckDaemonSocket([]string{missing, "unix://" + socket})
+	require.Equal(t, doctor.StatusPass, result.Status)
+	require.Contains(t, result.Message, socket)
+
+	require.Equal(t, doctor.StatusWarn, doctor.CheckDaemonSocket([]string{missing}).Status)
+}
+
+func TestCheckTempDir(t *testing.T) {
+	dir := t.TempDir()
+
+	require.Equal(t, doctor.StatusPass, doctor.CheckTempDir(dir, 0).Status)
+	require.Equal(t, doctor.StatusWarn, doctor.CheckTempDir(dir, 1<<62).Status)
+	require.Equal(t, doctor.StatusFail, doctor.CheckTempDir(filepath.Join(dir, "missing"), 0).Status)
+}
+
+func TestCheckVersion(t *testing.T) {
+	require.Equal(t, doctor.StatusPass, doctor.CheckVersion("1.2.0", false, "", nil).Status)
+	require.Equal(t, doctor.StatusWarn, doctor.CheckVersion("1.2.0", true, "1.3.0", nil).Status)
+	require.Equal(t, doctor.StatusWarn, doctor.CheckVersion("1.2.0", false, "", errors.New("offline")).Status)
+	require.Equal(t, doctor.StatusWarn, doctor.CheckVersion("", false, "", nil).Status)
+}
@@ -0,0 +1,48 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+const registryCheck = "registry"
+
+// CheckRegistryAuth will check that the image can be resolved from its registry with the local credentials.
+func CheckRegistryAuth(ctx context.Context, input string) Result {
+	result := Result{Name: registryCheck}
+
+	ref, err := name.ParseReference(input)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Invalid image reference %s: %v", input, err)
+
+		return result
+	}
+
+	desc, err := remote.Head(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Failed to resolve %s: %v", ref.Name(), err)
+		result.Hint = "check the network access to " + ref.Context().RegistryStr()
+
+		var terr *transport.Error
+		if errors.As(err, &terr) &&
+			(terr.StatusCode == http.StatusUnauthorized || terr.StatusCode == http.StatusForbidden) {
+			result.Hint = "log in with \"docker login " + ref.Context().RegistryStr() + "\""
+		}
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("Resolved %s to %s", ref.Name(), desc.Digest)
+
+	return result
+}
@@ -0,0 +1,96 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/gookit/color"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	// StatusPass means the check found nothing wrong.
+	StatusPass Status = "pass"
+	// StatusWarn means the check found something that may cause a failure.
+	StatusWarn Status = "warn"
+	// StatusFail means the check found something that will cause a failure.
+	StatusFail Status = "fail"
+
+	checkHeader   = "Check"
+	statusHeader  = "Status"
+	messageHeader = "Message"
+	hintHeader    = "Hint"
+)
+
+// Result is the result of a single check.
+type Result struct {
+	Name    string `json:"name"`
+	Status  Status `json:"status"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// Report is the list of check results to present.
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Add will append the result of a check to the report.
+func (r *Report) Add(result Result) {
+	r.Results = append(r.Results, result)
+}
+
+// Count returns the count of results with the status.
+func (r *Report) Count(status Status) int {
+	count := 0
+
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Title is the title of the report.
+func (r *Report) Title() string {
+	return "Diagnostics:"
+}
+
+// Footer summarizes the count of each status.
+func (r *Report) Footer() string {
+	return fmt.Sprintf("%d passed, %d warnings, %d failed\n",
+		r.Count(StatusPass), r.Count(StatusWarn), r.Count(StatusFail))
+}
+
+// Header is the header columns of the report.
+func (r *Report) Header() []string {
+	return []string{checkHeader, statusHeader, messageHeader, hintHeader}
+}
+
+// Rows returns all the check results as list of rows.
+func (r *Report) Rows() [][]string {
+	result := make([][]string, 0, len(r.Results))
+
+	for _, res := range r.Results {
+		result = append(result, []string{res.Name, res.Status.colorize(), res.Message, res.Hint})
+	}
+
+	return result
+}
+
+// colorize will color the status table item according the status.
+func (s Status) colorize() string {
+	switch s {
+	case StatusPass:
+		return color.Green.Sprint(string(s))
+	case StatusWarn:
+		return color.Yellow.Sprint(string(s))
+	case StatusFail:
+		return color.Red.Sprint(string(s))
+	default:
+		return string(s)
+	}
+}
@@ -0,0 +1,56 @@
+package doctor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"syscall"
+
+	"github.com/dustin/go-humanize"
+)
+
+const (
+	tempDirCheck = "temp_dir"
+
+	// MinTempDirSpace is the free space below which the image pulls may fail
+	MinTempDirSpace = 1 << 30
+)
+
+// CheckTempDir will check that the temp dir is writable and has room for the image pulls.
+func CheckTempDir(dir string, minFree uint64) Result {
+	result := Result{Name: tempDirCheck}
+
+	f, err := ioutil.TempFile(dir, "cbctl-doctor-")
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("%s not writable: %v", dir, err)
+		result.Hint = "set TMPDIR to a writable directory"
+
+		return result
+	}
+
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Failed to get the free space of %s: %v", dir, err)
+
+		return result
+	}
+
+	free := uint64(stat.Bavail) * uint64(stat.Bsize) // nolint: unconvert
+	if free < minFree {
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Only %s free in %s", humanize.Bytes(free), dir)
+		result.Hint = "free some space or set TMPDIR, the images are saved there while scanning"
+
+		return result
+	}
+
+	result.Status = StatusPass
+	result.Message = fmt.Sprintf("%s free in %s", humanize.Bytes(free), dir)
+
+	return result
+}
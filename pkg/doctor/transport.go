@@ -0,0 +1,56 @@
+package doctor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+const transportCheck = "transport"
+
+// CheckTransport will check the transport settings and show the effective ones for the target url.
+func CheckTransport(opts httptool.TransportOptions, target string) Result {
+	result := Result{Name: transportCheck}
+
+	if _, err := httptool.NewTransport(opts); err != nil {
+		result.Status = StatusFail
+		result.Message = cberr.ErrorMessage(err)
+		result.Hint = "fix the ca_bundle, client_cert and client_key config options"
+
+		return result
+	}
+
+	proxy, err := opts.ProxyFor(target)
+	if err != nil {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("Invalid proxy for %s: %v", target, err)
+		result.Hint = "fix the proxy_url config option or the HTTPS_PROXY env variable"
+
+		return result
+	}
+
+	if proxy == "" {
+		proxy = "direct"
+	}
+
+	settings := []string{fmt.Sprintf("proxy: %s", proxy)}
+
+	if opts.NoProxy != "" {
+		settings = append(settings, fmt.Sprintf("no_proxy: %s", opts.NoProxy))
+	}
+
+	if opts.CABundle != "" {
+		settings = append(settings, fmt.Sprintf("ca_bundle: %s", opts.CABundle))
+	}
+
+	if opts.ClientCert != "" {
+		settings = append(settings, fmt.Sprintf("client_cert: %s", opts.ClientCert))
+	}
+
+	result.Status = StatusPass
+	result.Message = strings.Join(settings, ", ")
+
+	return result
+}
@@ -0,0 +1,32 @@
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+const versionCheck = "version"
+
+// CheckVersion will report the skew between the current version and the latest one released.
+func CheckVersion(current string, updateAvailable bool, latest string, err error) Result {
+	result := Result{Name: versionCheck}
+
+	switch {
+	case current == "":
+		result.Status = StatusWarn
+		result.Message = "Development build, the version is unknown"
+	case err != nil:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Failed to fetch the latest version: %s", cberr.ErrorMessage(err))
+	case updateAvailable:
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Version %s is behind the latest %s", current, latest)
+		result.Hint = "upgrade cbctl to get the latest analysis features"
+	default:
+		result.Status = StatusPass
+		result.Message = fmt.Sprintf("Version %s is up to date", current)
+	}
+
+	return result
+}
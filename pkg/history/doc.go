@@ -0,0 +1,2 @@
+// Package history keeps the scan results in a local store, so they can be listed and compared later
+package history
@@ -0,0 +1,76 @@
+package history
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+const (
+	idHeader        = "ID"
+	imageHeader     = "Image"
+	digestHeader    = "Digest"
+	scannedAtHeader = "Scanned At"
+	profileHeader   = "Profile"
+
+	shortDigestLength = 19 // sha256: and the first 12 chars
+)
+
+// Entries is the list of history entries to present.
+type Entries struct {
+	Entries []Entry `json:"entries"`
+}
+
+// Title is the title of the history list.
+func (e *Entries) Title() string {
+	return "Local scan history:"
+}
+
+// Footer is empty for the history list.
+func (e *Entries) Footer() string {
+	return ""
+}
+
+// Header is the header columns of the history list.
+func (e *Entries) Header() []string {
+	return []string{
+		idHeader,
+		imageHeader,
+		digestHeader,
+		scannedAtHeader,
+		profileHeader,
+		image.SeverityCritical,
+		image.SeverityHigh,
+		image.SeverityMedium,
+		image.SeverityLow,
+		image.SeverityUnknown,
+	}
+}
+
+// Rows returns all the entries as list of rows.
+func (e *Entries) Rows() [][]string {
+	result := make([][]string, 0, len(e.Entries))
+
+	for _, entry := range e.Entries {
+		digest := entry.ManifestDigest
+		if len(digest) > shortDigestLength {
+			digest = digest[:shortDigestLength]
+		}
+
+		result = append(result, []string{
+			entry.ID,
+			entry.FullTag,
+			digest,
+			entry.Timestamp.Local().Format(time.RFC3339),
+			entry.Profile,
+			strconv.Itoa(entry.SeverityCounts[image.SeverityCritical]),
+			strconv.Itoa(entry.SeverityCounts[image.SeverityHigh]),
+			strconv.Itoa(entry.SeverityCounts[image.SeverityMedium]),
+			strconv.Itoa(entry.SeverityCounts[image.SeverityLow]),
+			strconv.Itoa(entry.SeverityCounts[image.SeverityUnknown]),
+		})
+	}
+
+	return result
+}
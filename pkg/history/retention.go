@@ -0,0 +1,45 @@
+package history
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultMaxEntries is the default max count of entries kept in the history.
+	DefaultMaxEntries = 100
+	// DefaultMaxAgeDays is the default max age in days of entries kept in the history.
+	DefaultMaxAgeDays = 90
+
+	day = 24 * time.Hour
+)
+
+// Retention is how many and how long the entries are kept in the history, a zero value means no limit.
+type Retention struct {
+	MaxEntries int
+	MaxAge     time.Duration
+}
+
+// ParseRetention will parse the retention from the config values, an empty or invalid value uses the default.
+func ParseRetention(maxEntries, maxAgeDays string) Retention {
+	return Retention{
+		MaxEntries: parseInt(maxEntries, DefaultMaxEntries),
+		MaxAge:     time.Duration(parseInt(maxAgeDays, DefaultMaxAgeDays)) * day,
+	}
+}
+
+func parseInt(value string, defaultValue int) int {
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		logrus.Warnf("Invalid history retention %q, using the default %d", value, defaultValue)
+		return defaultValue
+	}
+
+	return parsed
+}
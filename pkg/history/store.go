@@ -0,0 +1,298 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+const (
+	// DefaultStoreDirName is the name of the history folder under the config home.
+	DefaultStoreDirName = "history"
+
+	recordExt         = ".json"
+	idTimeLayout      = "20060102150405.000000"
+	idDigestLength    = 8
+	permModeReadWrite = 0600
+	permModeDir       = 0700
+)
+
+// Entry is the summary of a scan result in the history.
+type Entry struct {
+	ID             string         `json:"id"`
+	FullTag        string         `json:"full_tag"`
+	Repo           string         `json:"repo"`
+	ManifestDigest string         `json:"manifest_digest"`
+	Timestamp      time.Time      `json:"timestamp"`
+	Profile        string         `json:"profile"`
+	SeverityCounts map[string]int `json:"severity_counts"`
+}
+
+// Record is a scan result kept in the history.
+type Record struct {
+	Entry  `json:",inline"`
+	Result *image.ScannedImage `json:"result"`
+}
+
+// Store is the local folder of the scan history, one file per scan result. The first line of the file is the
+// entry, so that the history is listed without reading the results, the second line is the record.
+type Store struct {
+	dir string
+}
+
+// DefaultStorePath returns the path of the history folder under the given config home.
+func DefaultStorePath(configHome string) string {
+	return filepath.Join(configHome, DefaultStoreDirName)
+}
+
+// NewStore returns the store of the given folder, the folder is created on the first save.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Save will add the scan result to the history.
+func (s *Store) Save(result *image.ScannedImage, profile string, timestamp time.Time) (*Entry, error) {
+	record := Record{
+		Entry: Entry{
+			ID:             newID(result.ManifestDigest, timestamp),
+			FullTag:        result.FullTag,
+			Repo:           result.Repo,
+			ManifestDigest: result.ManifestDigest,
+			Timestamp:      timestamp.UTC(),
+			Profile:        profile,
+			SeverityCounts: severityCounts(result.Vulnerabilities),
+		},
+		Result: result,
+	}
+
+	header, err := json.Marshal(record.Entry)
+	if err != nil {
+		return nil, cberr.NewError(cberr.ConfigErr, "Failed to serialize scan result for the history", err)
+	}
+
+	content, err := json.Marshal(record)
+	if err != nil {
+		return nil, cberr.NewError(cberr.ConfigErr, "Failed to serialize scan result for the history", err)
+	}
+
+	if err := os.MkdirAll(s.dir, permModeDir); err != nil {
+		errMsg := fmt.Sprintf("Failed to create history folder %s", s.dir)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	content = append(append(header, '\n'), content...)
+	if err := s.writeRecord(record.ID, content); err != nil {
+		errMsg := fmt.Sprintf("Failed to save scan result %s to the history", record.ID)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return &record.Entry, nil
+}
+
+// writeRecord will write the record to a temp file renamed to the record file, so that a scan interrupted while
+// saving does not leave a partial record.
+func (s *Store) writeRecord(id string, content []byte) error {
+	file, err := ioutil.TempFile(s.dir, "."+id+"-*")
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Chmod(file.Name(), permModeReadWrite); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), s.recordPath(id))
+}
+
+// Load will read the scan result of the given id from the history.
+func (s *Store) Load(id string) (*Record, error) {
+	content, err := ioutil.ReadFile(s.recordPath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			errMsg := fmt.Sprintf("No scan result %s in the history", id)
+			return nil, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+		}
+
+		errMsg := fmt.Sprintf("Failed to read scan result %s from the history", id)
+
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	// the first line is the entry header, the record is the second
+	_, content, _ = bytes.Cut(content, []byte("\n"))
+
+	var record Record
+	if err := json.Unmarshal(content, &record); err != nil {
+		errMsg := fmt.Sprintf("Failed to parse scan result %s from the history", id)
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	return &record, nil
+}
+
+// List returns the entries in the history, newest first; if repo is not empty, only the entries of this repo.
+// The records which can not be read are skipped.
+func (s *Store) List(repo string) ([]Entry, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]Entry, 0, len(ids))
+
+	for _, id := range ids {
+		entry, err := s.loadEntry(id)
+		if err != nil {
+			logrus.WithField("id", id).Warnf("Skipping the scan result of the history: %v", err)
+			continue
+		}
+
+		if repo != "" && entry.Repo != repo && !strings.HasSuffix(entry.Repo, "/"+repo) {
+			continue
+		}
+
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.After(entries[j].Timestamp)
+	})
+
+	return entries, nil
+}
+
+// Prune will delete the entries beyond the retention, it returns the count of deleted entries.
+func (s *Store) Prune(retention Retention, now time.Time) (int, error) {
+	entries, err := s.List("")
+	if err != nil {
+		return 0, err
+	}
+
+	pruned := 0
+
+	for i, entry := range entries {
+		tooMany := retention.MaxEntries > 0 && i >= retention.MaxEntries
+		tooOld := retention.MaxAge > 0 && now.Sub(entry.Timestamp) > retention.MaxAge
+
+		if !tooMany && !tooOld {
+			continue
+		}
+
+		if err := os.Remove(s.recordPath(entry.ID)); err != nil {
+			errMsg := fmt.Sprintf("Failed to delete scan result %s from the history", entry.ID)
+			return pruned, cberr.NewError(cberr.ConfigErr, errMsg, err)
+		}
+
+		pruned++
+	}
+
+	return pruned, nil
+}
+
+// loadEntry will read the entry of the record from the first line of its file.
+func (s *Store) loadEntry(id string) (*Entry, error) {
+	file, err := os.Open(s.recordPath(id))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	line, err := bufio.NewReader(file).ReadBytes('\n')
+	if err != nil && len(line) == 0 {
+		return nil, err
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return nil, err
+	}
+
+	if entry.ID != id {
+		return nil, fmt.Errorf("the record holds the scan result %q", entry.ID)
+	}
+
+	return &entry, nil
+}
+
+func (s *Store) ids() ([]string, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		errMsg := fmt.Sprintf("Failed to read history folder %s", s.dir)
+
+		return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+	}
+
+	ids := make([]string, 0, len(files))
+
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != recordExt {
+			continue
+		}
+
+		ids = append(ids, strings.TrimSuffix(file.Name(), recordExt))
+	}
+
+	return ids, nil
+}
+
+func (s *Store) recordPath(id string) string {
+	// the id is used as a file name, so a path in it must not escape the history folder
+	return filepath.Join(s.dir, filepath.Base(id)+recordExt)
+}
+
+// newID returns a sortable id from the scan time and the manifest digest, e.g. 20210102150405.000000-1a2b3c4d,
+// the microseconds keep apart the scans of the same image in the same second.
+func newID(manifestDigest string, timestamp time.Time) string {
+	digest := strings.TrimPrefix(manifestDigest, "sha256:")
+	if len(digest) > idDigestLength {
+		digest = digest[:idDigestLength]
+	}
+
+	if digest == "" {
+		return timestamp.UTC().Format(idTimeLayout)
+	}
+
+	return fmt.Sprintf("%s-%s", timestamp.UTC().Format(idTimeLayout), digest)
+}
+
+func severityCounts(vulnerabilities []image.Vulnerability) map[string]int {
+	counts := make(map[string]int)
+
+	for _, vul := range vulnerabilities {
+		severity := strings.ToUpper(vul.Severity)
+		if !image.IsValidSeverity(severity) {
+			severity = image.SeverityUnknown
+		}
+
+		counts[severity]++
+	}
+
+	return counts
+}
@@ -0,0 +1,112 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/history"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+func TestSaveListLoad(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	start := time.Date(2021, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	first, err := store.Save(scannedImage("library/app", "sha256:1111111111", "HIGH"), "cbctl_default", start)
+	require.NoError(t, err)
+	require.Equal(t, "20210102150405.000000-11111111", first.ID)
+
+	// the same image scanned again in the same second
+	again, err := store.Save(scannedImage("library/app", "sha256:1111111111"), "cbctl_default",
+		start.Add(time.Millisecond))
+	require.NoError(t, err)
+	require.NotEqual(t, first.ID, again.ID)
+
+	second, err := store.Save(scannedImage("library/app", "sha256:2222222222", "CRITICAL", "low"), "cbctl_default",
+		start.Add(time.Hour))
+	require.NoError(t, err)
+
+	_, err = store.Save(scannedImage("library/other", "sha256:3333333333"), "cbctl_default", start.Add(time.Minute))
+	require.NoError(t, err)
+
+	entries, err := store.List("app")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.Equal(t, second.ID, entries[0].ID)
+	require.Equal(t, map[string]int{"CRITICAL": 1, "LOW": 1}, entries[0].SeverityCounts)
+
+	record, err := store.Load(first.ID)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:1111111111", record.Result.ManifestDigest)
+
+	_, err = store.Load("missing")
+	require.Error(t, err)
+}
+
+func TestPrune(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for i, digest := range []string{"sha256:aaaaaaaaaa", "sha256:bbbbbbbbbb", "sha256:cccccccccc"} {
+		_, err := store.Save(scannedImage("app", digest), "", now.Add(-time.Duration(i)*time.Hour))
+		require.NoError(t, err)
+	}
+
+	_, err := store.Save(scannedImage("app", "sha256:dddddddddd"), "", now.AddDate(0, 0, -100))
+	require.NoError(t, err)
+
+	pruned, err := store.Prune(history.ParseRetention("2", ""), now)
+	require.NoError(t, err)
+	require.Equal(t, 2, pruned)
+
+	entries, err := store.List("")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, "sha256:aaaaaaaaaa", entries[0].ManifestDigest)
+}
+
+func TestListSkipsBadRecords(t *testing.T) {
+	dir := t.TempDir()
+	store := history.NewStore(dir)
+	now := time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	older, err := store.Save(scannedImage("app", "sha256:bbbbbbbbbb"), "", now.Add(-time.Hour))
+	require.NoError(t, err)
+
+	saved, err := store.Save(scannedImage("app", "sha256:aaaaaaaaaa", "HIGH"), "", now)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20210102000000-cccccccc.json"), []byte(`{"id":`), 0600))
+
+	entries, err := store.List("")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, saved.ID, entries[0].ID)
+	require.Equal(t, older.ID, entries[1].ID)
+
+	record, err := store.Load(entries[1].ID)
+	require.NoError(t, err)
+	require.Equal(t, "sha256:bbbbbbbbbb", record.Result.ManifestDigest)
+
+	_, err = store.Load("20210102000000-cccccccc")
+	require.Error(t, err)
+
+	pruned, err := store.Prune(history.ParseRetention("1", ""), now)
+	require.NoError(t, err)
+	require.Equal(t, 1, pruned)
+}
+
+func scannedImage(repo, digest string, severities ...string) *image.ScannedImage {
+	result := &image.ScannedImage{
+		Identifier: image.Identifier{FullTag: repo + ":latest", Repo: repo, ManifestDigest: digest},
+	}
+
+	for _, severity := range severities {
+		result.Vulnerabilities = append(result.Vulnerabilities, image.Vulnerability{ID: "CVE", Severity: severity})
+	}
+
+	return result
+}
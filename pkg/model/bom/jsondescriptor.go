@@ -0,0 +1,8 @@
+package bom
+
+// JSONDescriptor describes the tool which generated the document, and its configuration.
+type JSONDescriptor struct {
+	Name          string      `json:"name"`
+	Version       string      `json:"version"`
+	Configuration interface{} `json:"configuration,omitempty"`
+}
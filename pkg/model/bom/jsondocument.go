@@ -15,6 +15,8 @@ type JSONDocument struct {
 	Source JSONSource `json:"source"`
 	// Distro represents the Linux distribution that was detected from the source
 	Distro JSONDistribution `json:"distro"`
+	// Descriptor describes the tool and its configuration, so that the cataloging can be reproduced
+	Descriptor *JSONDescriptor `json:"descriptor,omitempty"`
 }
 
 // NewJSONDocument creates and populates a new JSON document struct from the given cataloging results.
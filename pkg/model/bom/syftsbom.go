@@ -0,0 +1,87 @@
+package bom
+
+import (
+	"strings"
+
+	"github.com/anchore/syft/syft/cpe"
+	"github.com/anchore/syft/syft/linux"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+)
+
+// SyftSBOM converts the document back into a syft sbom, so that it can be encoded by the syft formats,
+// e.g. CycloneDX or SPDX. The relationships and the file metadata are not kept by the document.
+func (d JSONDocument) SyftSBOM() sbom.SBOM {
+	packages := make([]pkg.Package, 0, len(d.Artifacts))
+	for _, artifact := range d.Artifacts {
+		packages = append(packages, artifact.syftPackage())
+	}
+
+	var release *linux.Release
+	if d.Distro.Name != "" {
+		release = &linux.Release{
+			ID:        d.Distro.Name,
+			VersionID: d.Distro.Version,
+			IDLike:    strings.Fields(d.Distro.IDLike),
+		}
+	}
+
+	doc := sbom.SBOM{
+		Artifacts: sbom.Artifacts{
+			PackageCatalog:    pkg.NewCatalog(packages...),
+			LinuxDistribution: release,
+		},
+		Source: d.Source.syftMetadata(),
+	}
+
+	if d.Descriptor != nil {
+		doc.Descriptor = sbom.Descriptor{
+			Name:          d.Descriptor.Name,
+			Version:       d.Descriptor.Version,
+			Configuration: d.Descriptor.Configuration,
+		}
+	}
+
+	return doc
+}
+
+// syftPackage converts the package back into a syft package, the invalid CPEs are dropped.
+func (p JSONPackage) syftPackage() pkg.Package {
+	cpes := make([]cpe.CPE, 0, len(p.CPEs))
+
+	for _, value := range p.CPEs {
+		if c, err := cpe.New(value); err == nil {
+			cpes = append(cpes, c)
+		}
+	}
+
+	syftPackage := pkg.Package{
+		Name:         p.Name,
+		Version:      p.Version,
+		Type:         pkg.Type(p.Type),
+		FoundBy:      p.FoundBy,
+		Locations:    source.NewLocationSet(p.Locations...),
+		Licenses:     p.Licenses,
+		Language:     pkg.Language(p.Language),
+		CPEs:         cpes,
+		PURL:         p.PURL,
+		MetadataType: pkg.MetadataType(p.MetadataType),
+		Metadata:     p.Metadata,
+	}
+	syftPackage.SetID()
+
+	return syftPackage
+}
+
+// syftMetadata converts the source back into the syft source metadata.
+func (s JSONSource) syftMetadata() source.Metadata {
+	switch target := s.Target.(type) {
+	case JSONImageSource:
+		return source.Metadata{Scheme: source.ImageScheme, ImageMetadata: target.ImageMetadata}
+	case string:
+		return source.Metadata{Scheme: source.DirectoryScheme, Path: target}
+	default:
+		return source.Metadata{Scheme: source.UnknownScheme}
+	}
+}
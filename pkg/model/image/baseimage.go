@@ -0,0 +1,16 @@
+package image
+
+const (
+	// OriginBase marks a vulnerability inherited from the base image.
+	OriginBase = "base"
+	// OriginImage marks a vulnerability introduced by the image itself.
+	OriginImage = "image"
+)
+
+// BaseImage is the base image detected for a scanned image.
+type BaseImage struct {
+	// Name is the name of the base image from the base image catalog, e.g. alpine:3.13
+	Name string `json:"name"`
+	// MatchedLayers is the count of leading layers shared with the base image
+	MatchedLayers int `json:"matched_layers"`
+}
@@ -0,0 +1,11 @@
+package image
+
+// BaselineSummary is the comparison of a scanned image with the baseline of its repo.
+type BaselineSummary struct {
+	// Repo is the image repo the baseline is recorded for
+	Repo string `json:"repo"`
+	// Existing is the count of baseline findings still present in the image, they are not reported
+	Existing int `json:"existing"`
+	// Fixed is the count of baseline findings no longer present in the image
+	Fixed int `json:"fixed"`
+}
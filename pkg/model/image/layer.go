@@ -0,0 +1,27 @@
+package image
+
+import "github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
+
+// Layer is the summary of an image layer kept in the scan report.
+type Layer struct {
+	Digest  string `json:"digest"`
+	Command string `json:"command"`
+	Size    uint64 `json:"size"`
+	IsEmpty bool   `json:"is_empty"`
+}
+
+// NewLayers will summarize the given layers without their files.
+func NewLayers(imgLayers []layers.Layer) []Layer {
+	summaries := make([]Layer, 0, len(imgLayers))
+
+	for _, layer := range imgLayers {
+		summaries = append(summaries, Layer{
+			Digest:  layer.Digest,
+			Command: layer.Command,
+			Size:    layer.Size,
+			IsEmpty: layer.IsEmpty,
+		})
+	}
+
+	return summaries
+}
@@ -20,6 +20,7 @@ const (
 	fixAvailableHeader  = "Fix Available"
 	cvssV2Header        = "CVSS V2"
 	cvssV3Header        = "CVSS V3"
+	originHeader        = "Origin"
 )
 
 // ScannedImage response model from image scanning service.
@@ -31,6 +32,9 @@ type ScannedImage struct {
 	Vulnerabilities  []Vulnerability   `json:"vulnerabilities"`
 	PolicyViolations []PolicyViolation `json:"policy_violations,omitempty"`
 	Packages         bom.JSONDocument  `json:"packages"`
+	BaseImage        *BaseImage        `json:"base_image,omitempty"`
+	Layers           []Layer           `json:"layers,omitempty"`
+	Baseline         *BaselineSummary  `json:"baseline,omitempty"`
 }
 
 // Title is the title of the ScannedImage result.
@@ -40,7 +44,7 @@ func (s *ScannedImage) Title() string {
 
 // Header is the header columns of the ScannedImage result.
 func (s *ScannedImage) Header() []string {
-	return []string{
+	header := []string{
 		vulnerabilityHeader,
 		packageHeader,
 		typeHeader,
@@ -49,6 +53,41 @@ func (s *ScannedImage) Header() []string {
 		cvssV2Header,
 		cvssV3Header,
 	}
+
+	if s.BaseImage != nil {
+		header = append(header, originHeader)
+	}
+
+	return header
+}
+
+// Footer will summarize the baseline and the base image split, if any, before the overview link.
+func (s *ScannedImage) Footer() string {
+	footer := s.Identifier.Footer()
+
+	if s.BaseImage != nil {
+		footer = s.baseImageFooter() + footer
+	}
+
+	if s.Baseline != nil {
+		footer = fmt.Sprintf("%d new vulnerabilities, %d baseline findings of %s still exist, %d have been fixed\n%s",
+			len(s.Vulnerabilities), s.Baseline.Existing, s.Baseline.Repo, s.Baseline.Fixed, footer)
+	}
+
+	return footer
+}
+
+func (s *ScannedImage) baseImageFooter() string {
+	inherited := 0
+
+	for _, vul := range s.Vulnerabilities {
+		if vul.Origin == OriginBase {
+			inherited++
+		}
+	}
+
+	return fmt.Sprintf("%d vulnerabilities inherited from base %s, %d introduced by this image\n",
+		inherited, s.BaseImage.Name, len(s.Vulnerabilities)-inherited)
 }
 
 // Rows returns all the vulnerabilities of the ScannedImage result as list of rows.
@@ -58,7 +97,7 @@ func (s *ScannedImage) Rows() [][]string {
 	sortVulnerabilitiesBySeverities(s.Vulnerabilities)
 
 	for _, vul := range s.Vulnerabilities {
-		result = append(result, []string{
+		row := []string{
 			vul.GetID(),
 			vul.GetPackage(),
 			vul.GetType(),
@@ -66,7 +105,13 @@ func (s *ScannedImage) Rows() [][]string {
 			vul.GetFixAvailable(),
 			vul.GetCvssV2(),
 			vul.GetCvssV3(),
-		})
+		}
+
+		if s.BaseImage != nil {
+			row = append(row, vul.GetOrigin())
+		}
+
+		result = append(result, row)
 	}
 
 	return result
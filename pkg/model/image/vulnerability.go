@@ -35,6 +35,7 @@ type Vulnerability struct {
 	Description  string   `json:"description,omitempty" ,xml:"description,omitempty"`
 	FixAvailable string   `json:"fix_available" ,xml:"fix_available"`
 	Cvss         CvssItem `json:"cvss" ,xml:"cvss"`
+	Origin       string   `json:"origin,omitempty" ,xml:"origin,omitempty"`
 }
 
 // CvssItem denotes CVSS score.
@@ -78,6 +79,11 @@ func (v Vulnerability) GetCvssV3() string {
 	return fmt.Sprintf("%.1f", v.Cvss.V3)
 }
 
+// GetOrigin return whether the vulnerability is inherited from the base image or introduced by the image.
+func (v Vulnerability) GetOrigin() string {
+	return v.Origin
+}
+
 // colorizeSeverity will color the severity table item according the severity type.
 func (v Vulnerability) colorizeSeverity() string {
 	severity := strings.ToUpper(v.Severity)
@@ -98,23 +104,46 @@ func (v Vulnerability) colorizeSeverity() string {
 	}
 }
 
-func sortVulnerabilitiesBySeverities(vulnerabilities []Vulnerability) {
-	level := map[string]int{
-		SeverityCritical: 0,
-		SeverityHigh:     1,
-		SeverityMedium:   2,
-		SeverityLow:      3,
-		SeverityUnknown:  4,
+// SeverityLevel returns the rank of a severity, a lower level is more severe.
+func SeverityLevel(severity string) int {
+	switch strings.ToUpper(severity) {
+	case SeverityCritical:
+		return 0
+	case SeverityHigh:
+		return 1
+	case SeverityMedium:
+		return 2 // nolint: gomnd
+	case SeverityLow:
+		return 3 // nolint: gomnd
+	default:
+		return 4 // nolint: gomnd
 	}
+}
+
+// IsValidSeverity returns true if the input is one of the supported severities.
+func IsValidSeverity(severity string) bool {
+	switch strings.ToUpper(severity) {
+	case SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityUnknown:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSeverityAtLeast returns true if the vulnerability is at least as severe as the threshold.
+func (v Vulnerability) IsSeverityAtLeast(threshold string) bool {
+	return SeverityLevel(v.Severity) <= SeverityLevel(threshold)
+}
 
+func sortVulnerabilitiesBySeverities(vulnerabilities []Vulnerability) {
 	sort.Slice(vulnerabilities, func(i, j int) bool {
-		si := strings.ToUpper(vulnerabilities[i].Severity)
-		sj := strings.ToUpper(vulnerabilities[j].Severity)
+		li := SeverityLevel(vulnerabilities[i].Severity)
+		lj := SeverityLevel(vulnerabilities[j].Severity)
 
-		if level[si] == level[sj] {
+		if li == lj {
 			return vulnerabilities[i].FixAvailable != emptyFix
 		}
 
-		return level[si] < level[sj]
+		return li < lj
 	})
 }
@@ -29,6 +29,11 @@ func (p Presenter) Footer() string {
 	return p.provider.Footer()
 }
 
+// Result is the provider of the output, which holds the raw result.
+func (p Presenter) Result() interface{} {
+	return p.provider
+}
+
 // Present will convert the result into json format and pass to io.Writer.
 func (p Presenter) Present(output io.Writer) error {
 	doc, err := p.provider.CycloneDXDoc()
@@ -0,0 +1,83 @@
+// Package markdown provides utilities for showing results in markdown format
+package markdown
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gookit/color"
+)
+
+// Presenter will show the analysis result as a markdown table.
+type Presenter struct {
+	provider Provider
+}
+
+// NewPresenter will init a markdown presenter.
+func NewPresenter(provider Provider) *Presenter {
+	return &Presenter{
+		provider: provider,
+	}
+}
+
+// Title is the title of the markdown output.
+func (p Presenter) Title() string {
+	return p.provider.Title()
+}
+
+// Footer is the footer of the markdown output.
+func (p Presenter) Footer() string {
+	return p.provider.Footer()
+}
+
+// Result is the provider of the output, which holds the raw result.
+func (p Presenter) Result() interface{} {
+	return p.provider
+}
+
+// Present will convert the result into a markdown document and pass to io.Writer.
+func (p Presenter) Present(output io.Writer) error {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "### %s\n\n", cleanCell(p.provider.Title()))
+
+	header := p.provider.Header()
+	builder.WriteString(row(header))
+
+	separators := make([]string, len(header))
+	for i := range separators {
+		separators[i] = "---"
+	}
+
+	builder.WriteString(row(separators))
+
+	for _, r := range p.provider.Rows() {
+		builder.WriteString(row(r))
+	}
+
+	if footer := p.provider.Footer(); footer != "" {
+		fmt.Fprintf(&builder, "\n%s\n", color.ClearCode(footer))
+	}
+
+	_, err := io.WriteString(output, builder.String())
+
+	return err
+}
+
+func row(cells []string) string {
+	cleaned := make([]string, len(cells))
+	for i, cell := range cells {
+		cleaned[i] = cleanCell(cell)
+	}
+
+	return "| " + strings.Join(cleaned, " | ") + " |\n"
+}
+
+// cleanCell will remove the color codes and escape the characters breaking a markdown table.
+func cleanCell(cell string) string {
+	cell = color.ClearCode(cell)
+	cell = strings.ReplaceAll(cell, "|", "\\|")
+
+	return strings.ReplaceAll(cell, "\n", "<br>")
+}
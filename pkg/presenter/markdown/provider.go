@@ -0,0 +1,9 @@
+package markdown
+
+// Provider implement the methods needed for creating markdown.
+type Provider interface {
+	Title() string
+	Footer() string
+	Header() []string
+	Rows() [][]string
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter/cyclondx"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter/json"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter/markdown"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/presenter/table"
 )
 
@@ -24,7 +25,7 @@ type Provider interface {
 
 // Option is the option used for presenter.
 type Option struct {
-	// OutputFormat is the output format of result format (table, json) of the report
+	// OutputFormat is the output format of result format (table, json, markdown) of the report
 	OutputFormat string
 	// Limit is the number of rows to show in the result (table format only)
 	Limit int
@@ -37,9 +38,20 @@ func NewPresenter(provider Provider, opts Option) Presenter {
 		return json.NewPresenter(provider.(json.Provider))
 	case "cyclonedx", "c":
 		return cyclondx.NewPresenter(provider.(cyclondx.Provider))
+	case "markdown", "md":
+		return markdown.NewPresenter(provider.(markdown.Provider))
 	case "table", "t":
 		fallthrough
 	default:
 		return table.NewPresenter(provider.(table.Provider), table.Option{Limit: opts.Limit})
 	}
 }
+
+// Result returns the raw result behind the presenter, which can be marshaled into json.
+func Result(p Presenter) interface{} {
+	if r, ok := p.(interface{ Result() interface{} }); ok {
+		return r.Result()
+	}
+
+	return nil
+}
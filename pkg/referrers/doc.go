@@ -0,0 +1,3 @@
+// Package referrers pushes the sbom and the scan report of an image as OCI artifacts referencing its manifest,
+// and lists and fetches them back, through the OCI 1.1 referrers API or the referrers tag schema.
+package referrers
@@ -0,0 +1,406 @@
+package referrers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// The artifact types of the referrers pushed for a scanned image.
+const (
+	ArtifactTypeCycloneDX  = "application/vnd.cyclonedx+json"
+	ArtifactTypeSPDX       = "application/spdx+json"
+	ArtifactTypeScanReport = "application/vnd.carbonblack.container.scan-report.v1+json"
+)
+
+const (
+	// emptyConfigMediaType is the config of an artifact holding no config, its blob is {}
+	emptyConfigMediaType = "application/vnd.oci.empty.v1+json"
+	// annotationCreated is the creation time of the artifact
+	annotationCreated = "org.opencontainers.image.created"
+	// annotationTitle is the file name of the content of the artifact
+	annotationTitle = "org.opencontainers.image.title"
+)
+
+var emptyConfig = []byte("{}")
+
+// Artifact is the content pushed as a referrer of an image.
+type Artifact struct {
+	// ArtifactType is the media type of the content, e.g. application/vnd.cyclonedx+json
+	ArtifactType string
+	// Title is the file name of the content
+	Title string
+	// Content is the content of the artifact
+	Content []byte
+}
+
+// artifactManifest is an OCI 1.1 image manifest of an artifact, with its artifact type and subject.
+type artifactManifest struct {
+	specs.Versioned
+	MediaType    string                 `json:"mediaType"`
+	ArtifactType string                 `json:"artifactType"`
+	Config       imgspecv1.Descriptor   `json:"config"`
+	Layers       []imgspecv1.Descriptor `json:"layers"`
+	Subject      *imgspecv1.Descriptor  `json:"subject,omitempty"`
+	Annotations  map[string]string      `json:"annotations,omitempty"`
+}
+
+// rawManifest is a manifest put as is to the registry.
+type rawManifest struct {
+	content   []byte
+	mediaType types.MediaType
+}
+
+// RawManifest returns the content of the manifest.
+func (m rawManifest) RawManifest() ([]byte, error) {
+	return m.content, nil
+}
+
+// MediaType returns the media type of the manifest.
+func (m rawManifest) MediaType() (types.MediaType, error) {
+	return m.mediaType, nil
+}
+
+// Client pushes, lists and fetches the referrers of the images of a registry.
+type Client struct {
+	// auth is the authenticator of the registry, the docker config is used if it is nil
+	auth authn.Authenticator
+}
+
+// NewClient will create a client authenticated by auth, or by the credentials of the docker config if it is nil.
+func NewClient(auth authn.Authenticator) *Client {
+	return &Client{auth: auth}
+}
+
+// ResolveSubject will return the digest of the manifest of the image reference, the tag of a multi-platform image
+// is resolved to its linux/amd64 image like the image pull does.
+func (c *Client) ResolveSubject(ctx context.Context, reference string) (name.Digest, error) {
+	ref, err := name.ParseReference(reference)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	desc, err := remote.Get(ref, c.options(ctx)...)
+	if err != nil {
+		return name.Digest{}, err
+	}
+
+	digest := desc.Digest
+
+	if desc.MediaType.IsIndex() {
+		img, err := desc.Image()
+		if err != nil {
+			return name.Digest{}, err
+		}
+
+		if digest, err = img.Digest(); err != nil {
+			return name.Digest{}, err
+		}
+	}
+
+	return ref.Context().Digest(digest.String()), nil
+}
+
+// FindSubject will return the first of the digests whose manifest is in the repository, e.g. the manifest digest
+// of the scanned image then its repo digests.
+func (c *Client) FindSubject(ctx context.Context, repo name.Repository, digests ...string) (name.Digest, error) {
+	var lastErr error
+
+	for _, digest := range digests {
+		if _, err := godigest.Parse(digest); err != nil {
+			continue
+		}
+
+		subject := repo.Digest(digest)
+		if _, lastErr = remote.Head(subject, c.options(ctx)...); lastErr == nil {
+			return subject, nil
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no valid digest")
+	}
+
+	return name.Digest{}, fmt.Errorf("the image manifest is not found in %s: %w", repo, lastErr)
+}
+
+// Push will push the artifact as a referrer of the subject, and add it to the referrers tag schema if the registry
+// does not support the referrers api.
+func (c *Client) Push(ctx context.Context, subject name.Digest, artifact Artifact) (imgspecv1.Descriptor, error) {
+	repo := subject.Context()
+
+	subjectDesc, err := remote.Head(subject, c.options(ctx)...)
+	if err != nil {
+		return imgspecv1.Descriptor{}, fmt.Errorf("failed to read the subject %s: %w", subject, err)
+	}
+
+	config := static.NewLayer(emptyConfig, emptyConfigMediaType)
+	content := static.NewLayer(artifact.Content, types.MediaType(artifact.ArtifactType))
+
+	for _, layer := range []v1.Layer{config, content} {
+		if err := remote.WriteLayer(repo, layer, c.options(ctx)...); err != nil {
+			return imgspecv1.Descriptor{}, fmt.Errorf("failed to push the blob of %s: %w", artifact.Title, err)
+		}
+	}
+
+	annotations := map[string]string{annotationCreated: time.Now().UTC().Format(time.RFC3339)}
+	manifest := artifactManifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2}, // nolint: gomnd
+		MediaType:    imgspecv1.MediaTypeImageManifest,
+		ArtifactType: artifact.ArtifactType,
+		Config:       blobDescriptor(emptyConfigMediaType, emptyConfig, nil),
+		Layers: []imgspecv1.Descriptor{blobDescriptor(artifact.ArtifactType, artifact.Content,
+			map[string]string{annotationTitle: artifact.Title})},
+		Subject: &imgspecv1.Descriptor{
+			MediaType: string(subjectDesc.MediaType),
+			Digest:    godigest.Digest(subjectDesc.Digest.String()),
+			Size:      subjectDesc.Size,
+		},
+		Annotations: annotations,
+	}
+
+	raw, err := json.Marshal(manifest)
+	if err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+
+	desc := blobDescriptor(imgspecv1.MediaTypeImageManifest, raw, annotations)
+	desc.ArtifactType = artifact.ArtifactType
+
+	err = remote.Put(repo.Digest(desc.Digest.String()),
+		rawManifest{content: raw, mediaType: types.OCIManifestSchema1}, c.options(ctx)...)
+	if err != nil {
+		return imgspecv1.Descriptor{}, fmt.Errorf("failed to push the manifest of %s: %w", artifact.Title, err)
+	}
+
+	if _, supported, err := c.referrersFromAPI(ctx, subject); err != nil {
+		return desc, err
+	} else if supported {
+		return desc, nil
+	}
+
+	logrus.WithField("subject", subject.String()).Debug("Referrers api not supported, updating the referrers tag")
+
+	return desc, c.addToFallbackIndex(ctx, subject, desc)
+}
+
+// List will return the descriptors of the referrers of the subject, from the referrers api or the referrers tag.
+func (c *Client) List(ctx context.Context, subject name.Digest) ([]imgspecv1.Descriptor, error) {
+	index, supported, err := c.referrersFromAPI(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if !supported {
+		if index, err = c.fallbackIndex(ctx, subject); err != nil {
+			return nil, err
+		}
+	}
+
+	return index.Manifests, nil
+}
+
+// Fetch will return the artifact of the referrer of the repository.
+func (c *Client) Fetch(ctx context.Context, repo name.Repository, desc imgspecv1.Descriptor) (*Artifact, error) {
+	remoteDesc, err := remote.Get(repo.Digest(desc.Digest.String()), c.options(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest artifactManifest
+	if err := json.Unmarshal(remoteDesc.Manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest of the referrer %s: %w", desc.Digest, err)
+	}
+
+	if len(manifest.Layers) == 0 {
+		return nil, fmt.Errorf("the referrer %s has no content", desc.Digest)
+	}
+
+	layer, err := remote.Layer(repo.Digest(manifest.Layers[0].Digest.String()), c.options(ctx)...)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = reader.Close() }()
+
+	content, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactType := manifest.ArtifactType
+	if artifactType == "" {
+		artifactType = manifest.Config.MediaType
+	}
+
+	return &Artifact{
+		ArtifactType: artifactType,
+		Title:        manifest.Layers[0].Annotations[annotationTitle],
+		Content:      content,
+	}, nil
+}
+
+// referrersFromAPI will read the referrers of the subject from the referrers api, it reports if the registry
+// supports the api.
+func (c *Client) referrersFromAPI(ctx context.Context, subject name.Digest) (*imgspecv1.Index, bool, error) {
+	repo := subject.Context()
+
+	auth, err := c.authenticator(repo)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tr, err := transport.NewWithContext(ctx, repo.Registry, auth, remote.DefaultTransport,
+		[]string{repo.Scope(transport.PullScope)})
+	if err != nil {
+		return nil, false, err
+	}
+
+	url := fmt.Sprintf("%s://%s/v2/%s/referrers/%s",
+		repo.Registry.Scheme(), repo.RegistryStr(), repo.RepositoryStr(), subject.DigestStr())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	req.Header.Set("Accept", imgspecv1.MediaTypeImageIndex)
+
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+
+	if err := transport.CheckError(resp, http.StatusOK); err != nil {
+		return nil, false, err
+	}
+
+	var index imgspecv1.Index
+	if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+		return nil, true, fmt.Errorf("invalid referrers of %s: %w", subject, err)
+	}
+
+	return &index, true, nil
+}
+
+// fallbackIndex will read the index of the referrers tag of the subject, a missing tag is an empty index.
+func (c *Client) fallbackIndex(ctx context.Context, subject name.Digest) (*imgspecv1.Index, error) {
+	index := &imgspecv1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2}, // nolint: gomnd
+		MediaType: imgspecv1.MediaTypeImageIndex,
+		Manifests: []imgspecv1.Descriptor{},
+	}
+
+	desc, err := remote.Get(fallbackTag(subject), c.options(ctx)...)
+
+	var transportErr *transport.Error
+	if errors.As(err, &transportErr) && transportErr.StatusCode == http.StatusNotFound {
+		return index, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(desc.Manifest, index); err != nil {
+		return nil, fmt.Errorf("invalid referrers tag of %s: %w", subject, err)
+	}
+
+	return index, nil
+}
+
+// addToFallbackIndex will add the referrer to the index of the referrers tag of the subject.
+func (c *Client) addToFallbackIndex(ctx context.Context, subject name.Digest, desc imgspecv1.Descriptor) error {
+	index, err := c.fallbackIndex(ctx, subject)
+	if err != nil {
+		return err
+	}
+
+	for _, manifest := range index.Manifests {
+		if manifest.Digest == desc.Digest {
+			return nil
+		}
+	}
+
+	index.Manifests = append(index.Manifests, desc)
+
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	err = remote.Put(fallbackTag(subject), rawManifest{content: raw, mediaType: types.OCIImageIndex},
+		c.options(ctx)...)
+	if err != nil {
+		return fmt.Errorf("failed to update the referrers tag of %s: %w", subject, err)
+	}
+
+	return nil
+}
+
+func (c *Client) options(ctx context.Context) []remote.Option {
+	options := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithPlatform(v1.Platform{Architecture: "amd64", OS: "linux"}),
+	}
+
+	if c.auth != nil {
+		return append(options, remote.WithAuth(c.auth))
+	}
+
+	return append(options, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+}
+
+func (c *Client) authenticator(repo name.Repository) (authn.Authenticator, error) {
+	if c.auth != nil {
+		return c.auth, nil
+	}
+
+	return authn.DefaultKeychain.Resolve(repo)
+}
+
+// fallbackTag is the tag of the referrers index of the subject for the registries without the referrers api,
+// e.g. sha256-<hex>.
+func fallbackTag(subject name.Digest) name.Tag {
+	return subject.Context().Tag(strings.Replace(subject.DigestStr(), ":", "-", 1))
+}
+
+func blobDescriptor(mediaType string, content []byte, annotations map[string]string) imgspecv1.Descriptor {
+	hash := sha256.Sum256(content)
+
+	return imgspecv1.Descriptor{
+		MediaType:   mediaType,
+		Digest:      godigest.NewDigestFromBytes(godigest.SHA256, hash[:]),
+		Size:        int64(len(content)),
+		Annotations: annotations,
+	}
+}
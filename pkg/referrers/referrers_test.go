@@ -0,0 +1,160 @@
+package referrers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	godigest "github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/referrers"
+)
+
+// referrersAPI serves the referrers api in front of the in-process registry, which only supports the tag schema.
+type referrersAPI struct {
+	registry http.Handler
+
+	lock      sync.Mutex
+	referrers map[string][]imgspecv1.Descriptor
+}
+
+func (a *referrersAPI) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	elems := strings.Split(req.URL.Path, "/")
+
+	if req.Method == http.MethodGet && len(elems) > 3 && elems[len(elems)-2] == "referrers" {
+		a.lock.Lock()
+		index := imgspecv1.Index{MediaType: imgspecv1.MediaTypeImageIndex, Manifests: a.referrers[elems[len(elems)-1]]}
+		a.lock.Unlock()
+
+		resp.Header().Set("Content-Type", imgspecv1.MediaTypeImageIndex)
+		_ = json.NewEncoder(resp).Encode(index)
+
+		return
+	}
+
+	if req.Method == http.MethodPut && len(elems) > 3 && elems[len(elems)-2] == "manifests" {
+		body, _ := ioutil.ReadAll(req.Body)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		var manifest struct {
+			ArtifactType string                `json:"artifactType"`
+			Subject      *imgspecv1.Descriptor `json:"subject"`
+		}
+
+		if json.Unmarshal(body, &manifest) == nil && manifest.Subject != nil {
+			hash := sha256.Sum256(body)
+
+			a.lock.Lock()
+			a.referrers[manifest.Subject.Digest.String()] = append(a.referrers[manifest.Subject.Digest.String()],
+				imgspecv1.Descriptor{
+					MediaType:    imgspecv1.MediaTypeImageManifest,
+					Digest:       godigest.NewDigestFromBytes(godigest.SHA256, hash[:]),
+					Size:         int64(len(body)),
+					ArtifactType: manifest.ArtifactType,
+				})
+			a.lock.Unlock()
+		}
+	}
+
+	a.registry.ServeHTTP(resp, req)
+}
+
+func newRegistry(t *testing.T, withAPI bool) string {
+	var handler http.Handler = registry.New(registry.Logger(log.New(ioutil.Discard, "", 0)))
+	if withAPI {
+		handler = &referrersAPI{registry: handler, referrers: make(map[string][]imgspecv1.Descriptor)}
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+// pushImage will push a random image to the registry, and return its tag and its digest.
+func pushImage(t *testing.T, host string) (name.Tag, name.Digest) {
+	tag, err := name.NewTag(host + "/app:1.0")
+	require.NoError(t, err)
+
+	img, err := random.Image(256, 1)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(tag, img))
+
+	digest, err := img.Digest()
+	require.NoError(t, err)
+
+	return tag, tag.Context().Digest(digest.String())
+}
+
+func TestPushAndList(t *testing.T) {
+	for _, withAPI := range []bool{false, true} {
+		host := newRegistry(t, withAPI)
+		tag, digest := pushImage(t, host)
+		ctx := context.Background()
+		client := referrers.NewClient(authn.Anonymous)
+
+		subject, err := client.ResolveSubject(ctx, tag.String())
+		require.NoError(t, err)
+		require.Equal(t, digest.String(), subject.String())
+
+		// the manifest digest of an image of the daemon is not in the registry, its repo digest is
+		subject, err = client.FindSubject(ctx, tag.Context(),
+			"sha256:1111111111111111111111111111111111111111111111111111111111111111", digest.DigestStr())
+		require.NoError(t, err)
+		require.Equal(t, digest.String(), subject.String())
+
+		artifacts := []referrers.Artifact{
+			{ArtifactType: referrers.ArtifactTypeCycloneDX, Title: "sbom.cdx.json", Content: []byte(`{}`)},
+			{ArtifactType: referrers.ArtifactTypeScanReport, Title: "report.json", Content: []byte(`[]`)},
+		}
+
+		for _, artifact := range artifacts {
+			_, err := client.Push(ctx, subject, artifact)
+			require.NoError(t, err, withAPI)
+		}
+
+		listed, err := client.List(ctx, subject)
+		require.NoError(t, err)
+		require.Len(t, listed, 2, withAPI)
+
+		for i, desc := range listed {
+			require.Equal(t, artifacts[i].ArtifactType, desc.ArtifactType)
+
+			fetched, err := client.Fetch(ctx, subject.Context(), desc)
+			require.NoError(t, err)
+			require.Equal(t, artifacts[i], *fetched)
+		}
+
+		// the referrers tag is only used without the referrers api
+		_, err = remote.Head(subject.Context().Tag(strings.Replace(subject.DigestStr(), ":", "-", 1)))
+		require.Equal(t, !withAPI, err == nil, withAPI)
+	}
+}
+
+func TestListNoReferrers(t *testing.T) {
+	host := newRegistry(t, false)
+	_, digest := pushImage(t, host)
+	client := referrers.NewClient(authn.Anonymous)
+
+	listed, err := client.List(context.Background(), digest)
+	require.NoError(t, err)
+	require.Empty(t, listed)
+
+	_, err = client.FindSubject(context.Background(), digest.Context(),
+		"sha256:1111111111111111111111111111111111111111111111111111111111111111")
+	require.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package referrers
+
+import (
+	"fmt"
+
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	digestHeader       = "Digest"
+	artifactTypeHeader = "Artifact Type"
+	sizeHeader         = "Size"
+	createdHeader      = "Created"
+	fileHeader         = "File"
+)
+
+// Referrer is an artifact referencing the image.
+type Referrer struct {
+	Digest       string `json:"digest"`
+	ArtifactType string `json:"artifact_type"`
+	Size         int64  `json:"size"`
+	Created      string `json:"created,omitempty"`
+	// File is where the content is fetched to, if it is fetched
+	File string `json:"file,omitempty"`
+}
+
+// Report is the list of the referrers of an image.
+type Report struct {
+	Subject   string     `json:"subject"`
+	Referrers []Referrer `json:"referrers"`
+}
+
+// NewReferrer will create the referrer of the descriptor listed by the registry.
+func NewReferrer(desc imgspecv1.Descriptor) Referrer {
+	return Referrer{
+		Digest:       desc.Digest.String(),
+		ArtifactType: desc.ArtifactType,
+		Size:         desc.Size,
+		Created:      desc.Annotations[annotationCreated],
+	}
+}
+
+// Title is the title of the report.
+func (r *Report) Title() string {
+	return fmt.Sprintf("Referrers of %s:", r.Subject)
+}
+
+// Footer summarizes the count of referrers.
+func (r *Report) Footer() string {
+	return fmt.Sprintf("%d referrers found\n", len(r.Referrers))
+}
+
+// Header is the header columns of the report, the file is only shown once the referrers are fetched.
+func (r *Report) Header() []string {
+	header := []string{digestHeader, artifactTypeHeader, sizeHeader, createdHeader}
+	if r.isFetched() {
+		header = append(header, fileHeader)
+	}
+
+	return header
+}
+
+// Rows returns all the referrers as list of rows.
+func (r *Report) Rows() [][]string {
+	result := make([][]string, 0, len(r.Referrers))
+
+	for _, referrer := range r.Referrers {
+		row := []string{referrer.Digest, referrer.ArtifactType, fmt.Sprint(referrer.Size), referrer.Created}
+		if r.isFetched() {
+			row = append(row, referrer.File)
+		}
+
+		result = append(result, row)
+	}
+
+	return result
+}
+
+func (r *Report) isFetched() bool {
+	for _, referrer := range r.Referrers {
+		if referrer.File != "" {
+			return true
+		}
+	}
+
+	return false
+}
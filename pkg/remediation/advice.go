@@ -0,0 +1,255 @@
+package remediation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	hashiVersion "github.com/hashicorp/go-version"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+)
+
+const (
+	packageHeader   = "Package"
+	currentHeader   = "Current Version"
+	fixedHeader     = "Fixed Version"
+	resolvesHeader  = "Resolves CVEs"
+	upgradeHeader   = "Upgrade"
+	noFixIndication = "none"
+)
+
+// Upgrade is a single package upgrade resolving one or more vulnerabilities.
+type Upgrade struct {
+	Package         string    `json:"package"`
+	Type            string    `json:"package_type"`
+	Ecosystem       Ecosystem `json:"ecosystem"`
+	CurrentVersion  string    `json:"current_version"`
+	FixedVersion    string    `json:"fixed_version"`
+	Vulnerabilities []string  `json:"vulnerabilities"`
+	Command         string    `json:"command"`
+}
+
+// Advice is the remediation advice for a scanned image.
+type Advice struct {
+	image.Identifier `json:",inline"`
+	Upgrades         []Upgrade `json:"upgrades"`
+	Dockerfile       string    `json:"dockerfile,omitempty"`
+	// Unfixable is the count of vulnerabilities with no fix available
+	Unfixable int `json:"unfixable"`
+}
+
+// NewAdvice will group the fixable vulnerabilities of the scanned image by package into upgrades.
+func NewAdvice(scannedImage *image.ScannedImage, withDockerfile bool) *Advice {
+	advice := &Advice{
+		Identifier: scannedImage.Identifier,
+		Upgrades:   make([]Upgrade, 0),
+	}
+
+	purls := make(map[string]string, len(scannedImage.Packages.Artifacts))
+	types := make(map[string]string, len(scannedImage.Packages.Artifacts))
+
+	for _, artifact := range scannedImage.Packages.Artifacts {
+		key := artifact.Name + "@" + artifact.Version
+		purls[key] = artifact.PURL
+		types[key] = artifact.Type
+	}
+
+	upgrades := make(map[string]*Upgrade)
+	keys := make([]string, 0)
+
+	for _, vul := range scannedImage.Vulnerabilities {
+		if !isFixAvailable(vul.FixAvailable) {
+			advice.Unfixable++
+			continue
+		}
+
+		key := vul.Name + "@" + vul.Version
+
+		upgrade, ok := upgrades[key]
+		if !ok {
+			packageType := types[key]
+			if packageType == "" {
+				packageType = vul.Type
+			}
+
+			upgrade = &Upgrade{
+				Package:        vul.Name,
+				Type:           packageType,
+				Ecosystem:      detectEcosystem(packageType, scannedImage.Packages.Distro),
+				CurrentVersion: vul.Version,
+			}
+			upgrades[key] = upgrade
+			keys = append(keys, key)
+		}
+
+		upgrade.Vulnerabilities = appendUnique(upgrade.Vulnerabilities, vul.ID)
+
+		for _, fixedVersion := range strings.Split(vul.FixAvailable, ",") {
+			upgrade.FixedVersion = highestVersion(upgrade.FixedVersion, strings.TrimSpace(fixedVersion))
+		}
+	}
+
+	for _, key := range keys {
+		upgrade := upgrades[key]
+		upgrade.Command = upgradeCommand(upgrade.Ecosystem, upgrade.Package, purls[key], upgrade.FixedVersion)
+		advice.Upgrades = append(advice.Upgrades, *upgrade)
+	}
+
+	// the upgrades resolving the most vulnerabilities come first
+	sort.SliceStable(advice.Upgrades, func(i, j int) bool {
+		return len(advice.Upgrades[i].Vulnerabilities) > len(advice.Upgrades[j].Vulnerabilities)
+	})
+
+	if withDockerfile {
+		advice.Dockerfile = dockerfileSnippet(advice.Upgrades)
+	}
+
+	return advice
+}
+
+// Title is the title of the remediation advice.
+func (a *Advice) Title() string {
+	return fmt.Sprintf("Remediation for %s (%s):", a.FullTag, a.ManifestDigest)
+}
+
+// Header is the header columns of the remediation advice.
+func (a *Advice) Header() []string {
+	return []string{
+		packageHeader,
+		currentHeader,
+		fixedHeader,
+		resolvesHeader,
+		upgradeHeader,
+	}
+}
+
+// Rows returns all the upgrades as list of rows.
+func (a *Advice) Rows() [][]string {
+	result := make([][]string, 0, len(a.Upgrades))
+
+	for _, upgrade := range a.Upgrades {
+		result = append(result, []string{
+			upgrade.Package,
+			upgrade.CurrentVersion,
+			upgrade.FixedVersion,
+			strconv.Itoa(len(upgrade.Vulnerabilities)),
+			upgrade.Command,
+		})
+	}
+
+	return result
+}
+
+// Footer will show the unfixable count and the Dockerfile snippet, if requested.
+func (a *Advice) Footer() string {
+	footer := fmt.Sprintf("%d upgrades found, %d vulnerabilities have no fix available", len(a.Upgrades), a.Unfixable)
+
+	if a.Dockerfile != "" {
+		footer += "\n\nDockerfile snippet:\n" + a.Dockerfile
+	}
+
+	return footer
+}
+
+// dockerfileSnippet will merge the upgrades of each ecosystem into a single instruction.
+func dockerfileSnippet(upgrades []Upgrade) string {
+	byEcosystem := make(map[Ecosystem][]Upgrade)
+	for _, upgrade := range upgrades {
+		byEcosystem[upgrade.Ecosystem] = append(byEcosystem[upgrade.Ecosystem], upgrade)
+	}
+
+	lines := make([]string, 0)
+
+	for _, ecosystem := range []Ecosystem{Apk, Apt, Yum, Pip, Npm, Gem, Go, Maven, Unknown} {
+		ecosystemUpgrades := byEcosystem[ecosystem]
+		if len(ecosystemUpgrades) == 0 {
+			continue
+		}
+
+		args := make([]string, 0, len(ecosystemUpgrades))
+
+		for _, upgrade := range ecosystemUpgrades {
+			// every command ends with the package argument(s), drop the leading program and sub-command
+			command := upgradeCommand(ecosystem, upgrade.Package, "", upgrade.FixedVersion)
+
+			switch ecosystem {
+			case Apk, Yum, Pip, Npm, Go:
+				args = append(args, command[strings.LastIndex(command, " ")+1:])
+			case Apt:
+				args = append(args, strings.TrimPrefix(command, "apt-get install -y "))
+			case Gem, Maven, Unknown:
+				lines = append(lines, fmt.Sprintf("# %s", upgrade.Command))
+			}
+		}
+
+		if len(args) == 0 {
+			continue
+		}
+
+		joined := strings.Join(args, " ")
+
+		switch ecosystem {
+		case Apk:
+			lines = append(lines, "RUN apk add --no-cache --upgrade "+joined)
+		case Apt:
+			lines = append(lines, "RUN apt-get update && apt-get install -y --only-upgrade "+joined+
+				" && rm -rf /var/lib/apt/lists/*")
+		case Yum:
+			lines = append(lines, "RUN yum update -y "+joined+" && yum clean all")
+		case Pip:
+			lines = append(lines, "RUN pip install --no-cache-dir "+joined)
+		case Npm:
+			lines = append(lines, "RUN npm install "+joined)
+		case Go:
+			lines = append(lines, "RUN go get "+joined)
+		case Gem, Maven, Unknown:
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func isFixAvailable(fixAvailable string) bool {
+	fix := strings.ToLower(strings.TrimSpace(fixAvailable))
+
+	return fix != "" && fix != noFixIndication && !strings.HasPrefix(fix, "not ")
+}
+
+// highestVersion returns the higher one of the two versions, falling back to string comparison.
+func highestVersion(a, b string) string {
+	if a == "" {
+		return b
+	}
+
+	if b == "" {
+		return a
+	}
+
+	va, errA := hashiVersion.NewVersion(a)
+	vb, errB := hashiVersion.NewVersion(b)
+
+	if errA == nil && errB == nil {
+		if vb.GreaterThan(va) {
+			return b
+		}
+
+		return a
+	}
+
+	if b > a {
+		return b
+	}
+
+	return a
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+
+	return append(values, value)
+}
@@ -0,0 +1,58 @@
+package remediation_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/remediation"
+)
+
+func TestNewAdviceGroupsByPackage(t *testing.T) {
+	scannedImage := &image.ScannedImage{
+		Vulnerabilities: []image.Vulnerability{
+			{ID: "CVE-1", Name: "openssl", Version: "1.1.1k-r0", FixAvailable: "1.1.1l-r0"},
+			{ID: "CVE-2", Name: "openssl", Version: "1.1.1k-r0", FixAvailable: "1.1.1n-r0"},
+			{ID: "CVE-3", Name: "flask", Version: "1.0", Type: "python", FixAvailable: "2.0.1"},
+			{ID: "CVE-4", Name: "log4j-core", Version: "2.14.0", FixAvailable: "2.17.1"},
+			{ID: "CVE-5", Name: "busybox", Version: "1.32", FixAvailable: ""},
+			{ID: "CVE-6", Name: "hyper", Version: "0.14.9", Type: "rust-crate", FixAvailable: "0.14.10"},
+		},
+		Packages: bom.JSONDocument{
+			Artifacts: []bom.JSONPackage{
+				{Name: "openssl", Version: "1.1.1k-r0", Type: "apk"},
+				{
+					Name: "log4j-core", Version: "2.14.0", Type: "java-archive",
+					PURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.0",
+				},
+			},
+			Distro: bom.JSONDistribution{Name: "alpine", Version: "3.13"},
+		},
+	}
+
+	advice := remediation.NewAdvice(scannedImage, true)
+
+	require.Equal(t, 1, advice.Unfixable)
+	require.Len(t, advice.Upgrades, 4)
+
+	openssl := advice.Upgrades[0]
+	require.Equal(t, "openssl", openssl.Package)
+	require.Equal(t, "1.1.1n-r0", openssl.FixedVersion)
+	require.Equal(t, []string{"CVE-1", "CVE-2"}, openssl.Vulnerabilities)
+	require.Equal(t, "apk add --upgrade 'openssl>=1.1.1n-r0'", openssl.Command)
+
+	commands := make(map[string]string)
+	for _, upgrade := range advice.Upgrades {
+		commands[upgrade.Package] = upgrade.Command
+	}
+
+	require.Equal(t, "pip install 'flask==2.0.1'", commands["flask"])
+	require.Equal(t, "org.apache.logging.log4j:log4j-core:2.17.1", commands["log4j-core"])
+	// a package which is not an os package is not upgraded by the package manager of the distro
+	require.Equal(t, "upgrade hyper to 0.14.10", commands["hyper"])
+
+	require.True(t, strings.Contains(advice.Dockerfile, "RUN apk add --no-cache --upgrade 'openssl>=1.1.1n-r0'"))
+	require.True(t, strings.Contains(advice.Dockerfile, "RUN pip install --no-cache-dir 'flask==2.0.1'"))
+}
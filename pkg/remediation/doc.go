@@ -0,0 +1,2 @@
+// Package remediation turns the fixable vulnerabilities of a scanned image into package-manager upgrade commands
+package remediation
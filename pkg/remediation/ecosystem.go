@@ -0,0 +1,116 @@
+package remediation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+)
+
+// Ecosystem is the package manager used for upgrading a package.
+type Ecosystem string
+
+// All the supported ecosystems.
+const (
+	Apk     Ecosystem = "apk"
+	Apt     Ecosystem = "apt"
+	Yum     Ecosystem = "yum"
+	Pip     Ecosystem = "pip"
+	Npm     Ecosystem = "npm"
+	Maven   Ecosystem = "maven"
+	Gem     Ecosystem = "gem"
+	Go      Ecosystem = "go"
+	Unknown Ecosystem = "unknown"
+)
+
+// osPackageType is the type of the os packages in the vulnerabilities of the backend.
+const osPackageType = "os"
+
+// detectEcosystem picks the package manager from the package type, falling back to the distro for os packages
+// and packages without type only, e.g. a rust crate is not upgraded by the os package manager.
+func detectEcosystem(packageType string, distro bom.JSONDistribution) Ecosystem {
+	switch pkg.Type(strings.ToLower(packageType)) {
+	case pkg.ApkPkg:
+		return Apk
+	case pkg.DebPkg:
+		return Apt
+	case pkg.RpmPkg:
+		return Yum
+	case pkg.PythonPkg:
+		return Pip
+	case pkg.NpmPkg:
+		return Npm
+	case pkg.JavaPkg, pkg.JenkinsPluginPkg:
+		return Maven
+	case pkg.GemPkg:
+		return Gem
+	case pkg.GoModulePkg:
+		return Go
+	case "", osPackageType, pkg.AlpmPkg, pkg.PortagePkg:
+		return distroEcosystem(distro)
+	default:
+		return Unknown
+	}
+}
+
+// distroEcosystem returns the os package manager of the distro.
+func distroEcosystem(distro bom.JSONDistribution) Ecosystem {
+	ids := append([]string{distro.Name}, strings.Fields(distro.IDLike)...)
+
+	for _, id := range ids {
+		switch strings.ToLower(id) {
+		case "alpine", "wolfi":
+			return Apk
+		case "debian", "ubuntu":
+			return Apt
+		case "rhel", "centos", "fedora", "amzn", "ol", "rocky", "almalinux":
+			return Yum
+		}
+	}
+
+	return Unknown
+}
+
+// upgradeCommand returns the command line upgrading a single package to the fixed version.
+func upgradeCommand(ecosystem Ecosystem, name, purl, fixedVersion string) string {
+	switch ecosystem {
+	case Apk:
+		return fmt.Sprintf("apk add --upgrade '%s>=%s'", name, fixedVersion)
+	case Apt:
+		return fmt.Sprintf("apt-get install -y %s=%s", name, fixedVersion)
+	case Yum:
+		return fmt.Sprintf("yum update -y %s-%s", name, fixedVersion)
+	case Pip:
+		return fmt.Sprintf("pip install '%s==%s'", name, fixedVersion)
+	case Npm:
+		return fmt.Sprintf("npm install %s@%s", name, fixedVersion)
+	case Maven:
+		return fmt.Sprintf("%s:%s", mavenCoordinates(name, purl), fixedVersion)
+	case Gem:
+		return fmt.Sprintf("gem install %s -v %s", name, fixedVersion)
+	case Go:
+		return fmt.Sprintf("go get %s@%s", name, fixedVersion)
+	case Unknown:
+		fallthrough
+	default:
+		return fmt.Sprintf("upgrade %s to %s", name, fixedVersion)
+	}
+}
+
+// mavenCoordinates returns the group:artifact of a java package, using the purl when it is available.
+func mavenCoordinates(name, purl string) string {
+	// purl format: pkg:maven/<group>/<artifact>@<version>
+	const mavenPrefix = "pkg:maven/"
+
+	if !strings.HasPrefix(purl, mavenPrefix) {
+		return name
+	}
+
+	coordinates := strings.TrimPrefix(purl, mavenPrefix)
+	if i := strings.IndexAny(coordinates, "@?#"); i >= 0 {
+		coordinates = coordinates[:i]
+	}
+
+	return strings.ReplaceAll(coordinates, "/", ":")
+}
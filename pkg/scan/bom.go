@@ -4,12 +4,13 @@ import (
 	"fmt"
 	"github.com/anchore/stereoscope/pkg/image"
 	"github.com/anchore/syft/syft"
-	"github.com/anchore/syft/syft/pkg/cataloger"
 	"github.com/anchore/syft/syft/source"
 	"github.com/containers/image/v5/docker/reference"
 	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+	"os"
 	"strings"
 )
 
@@ -24,8 +25,14 @@ type Bom struct {
 	Packages bom.JSONDocument
 }
 
-// GenerateSBOMFromImage runs the image through syft's catalogers and returns a populated SBOM of the found packages
-func GenerateSBOMFromImage(img *image.Image, originalInput, forceFullTag string) (*Bom, error) {
+// GenerateSBOMFromImage runs the image through syft's catalogers and returns a populated SBOM of the found packages,
+// the cataloging option is recorded in the descriptor of the SBOM
+func GenerateSBOMFromImage(img *image.Image, originalInput, forceFullTag string, catalog CatalogOption) (*Bom, error) {
+	cft, err := catalog.syftConfig(source.ImageScheme)
+	if err != nil {
+		return nil, err
+	}
+
 	theSource, err := source.NewFromImage(img, originalInput)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to create image source from input %v", originalInput)
@@ -35,7 +42,7 @@ func GenerateSBOMFromImage(img *image.Image, originalInput, forceFullTag string)
 		return nil, e
 	}
 
-	cft := cataloger.DefaultConfig()
+	theSource.Exclusions = catalog.Exclusions
 
 	theCatalog, _, linuxDistro, err := syft.CatalogPackages(&theSource, cft)
 	if err != nil {
@@ -47,7 +54,7 @@ func GenerateSBOMFromImage(img *image.Image, originalInput, forceFullTag string)
 		return nil, e
 	}
 
-	doc, err := bom.NewJSONDocument(theCatalog, theSource.Metadata, linuxDistro, source.SquashedScope)
+	doc, err := bom.NewJSONDocument(theCatalog, theSource.Metadata, linuxDistro, cft.Search.Scope)
 	if err != nil {
 		errMsg := fmt.Sprintf("Failed to parse the sbom for %v", originalInput)
 		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
@@ -83,6 +90,11 @@ func GenerateSBOMFromImage(img *image.Image, originalInput, forceFullTag string)
 	}
 
 	doc.Source.Target = target
+	doc.Descriptor = &bom.JSONDescriptor{
+		Name:          "syft",
+		Version:       version.GetCurrentVersion().SyftVersion,
+		Configuration: catalog,
+	}
 
 	logrus.WithField("fullTag", fullTag).Infof("SBOM generated successfully")
 
@@ -144,8 +156,15 @@ func revertAnchoreDigestChange(anchorNormalizedTag string) string {
 func generateFullTagFromOriginInput(tag string, manifestDigest string) string {
 	// since we already generated sbom with this input with sha256, the input can be guaranteed as
 	// an image with valid sha256 digest, no need to further checking here
-	if strings.Contains(tag, tarFileEnding) {
-		repo := strings.TrimSuffix(tag[strings.LastIndex(tag, "/")+1:], tarFileEnding)
+	location := tag
+	if parts := strings.SplitN(tag, schemeSeparator, 2); len(parts) == 2 && isArchiveScheme(parts[0]) {
+		location = parts[1]
+	}
+
+	// an archive or a layout is named after its file, e.g. oci-dir:build/layout:@1 or path/to/image.tar
+	location, _ = splitSelector(location)
+	if _, err := os.Stat(location); err == nil || location != tag || strings.Contains(tag, tarFileEnding) {
+		repo := directoryName(strings.TrimSuffix(location, tarFileEnding))
 		newTag := strings.ReplaceAll(manifestDigest, "sha256:", "")
 		tag = fmt.Sprintf("%s:%s", repo, newTag)
 	}
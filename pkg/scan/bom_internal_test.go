@@ -1,6 +1,7 @@
 package scan
 
 import (
+	"context"
 	"crypto/sha256"
 	"fmt"
 	"github.com/smartystreets/goconvey/convey"
@@ -29,10 +30,10 @@ var (
 func TestGenerateBomOk(t *testing.T) {
 	convey.Convey("BOM generation wil valid input", t, func() {
 		registryHandler := NewRegistryHandler()
-		img, err := registryHandler.LoadImage(testImageAlpineTar, Option{})
+		img, err := registryHandler.LoadImage(context.Background(), testImageAlpineTar, Option{})
 		convey.So(err, convey.ShouldBeNil)
 
-		bom, err := GenerateSBOMFromImage(img, testImageAlpineTar, "")
+		bom, err := GenerateSBOMFromImage(img, testImageAlpineTar, "", DefaultCatalogOption())
 		convey.So(err, convey.ShouldBeNil)
 		convey.So(bom, convey.ShouldNotBeNil)
 	})
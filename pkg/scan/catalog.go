@@ -0,0 +1,164 @@
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/pkg/cataloger"
+	"github.com/anchore/syft/syft/source"
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+// The layers of the image which are cataloged.
+const (
+	// ScopeSquashed only catalogs the files visible in the container at runtime
+	ScopeSquashed = "squashed"
+	// ScopeAllLayers catalogs the files of all the layers, including the ones removed by a later layer
+	ScopeAllLayers = "all-layers"
+)
+
+// The archives searched for packages, e.g. nested jars or zips.
+const (
+	// ArchivesIndexed only searches the archives with an index, e.g. jar, war, zip
+	ArchivesIndexed = "indexed"
+	// ArchivesAll also searches the archives without an index, e.g. tar.gz
+	ArchivesAll = "all"
+	// ArchivesNone does not search into any archive
+	ArchivesNone = "none"
+)
+
+// excludedCatalogerPrefix marks a cataloger to disable in the list of catalogers.
+const excludedCatalogerPrefix = "-"
+
+// CatalogOption is the option of the cataloging of the packages, which is recorded in the sbom
+// so that the result can be reproduced.
+type CatalogOption struct {
+	// Scope is the layers of the image cataloged: squashed or all-layers
+	Scope string `json:"scope"`
+	// Catalogers are the catalogers enabled, or disabled if prefixed with "-", a part of the name is enough
+	Catalogers []string `json:"catalogers,omitempty"`
+	// Exclusions are the globs of the paths not cataloged, e.g. **/test/**, from the root of the image or directory
+	Exclusions []string `json:"exclusions,omitempty"`
+	// SearchArchives is the archives searched for packages: indexed, all or none
+	SearchArchives string `json:"search_archives"`
+}
+
+// DefaultCatalogOption returns the cataloging option used if none is set.
+func DefaultCatalogOption() CatalogOption {
+	return CatalogOption{
+		Scope:          ScopeSquashed,
+		SearchArchives: ArchivesIndexed,
+	}
+}
+
+// IsDefault reports if the option catalogs the same packages as the default one.
+func (o CatalogOption) IsDefault() bool {
+	d := DefaultCatalogOption()
+
+	return (o.Scope == "" || o.Scope == d.Scope) &&
+		(o.SearchArchives == "" || o.SearchArchives == d.SearchArchives) &&
+		len(o.Catalogers) == 0 && len(o.Exclusions) == 0
+}
+
+// Validate will check the scope, the archives and the globs of the exclusions.
+func (o CatalogOption) Validate() error {
+	switch o.Scope {
+	case "", ScopeSquashed, ScopeAllLayers:
+	default:
+		errMsg := fmt.Sprintf("Invalid scope %q, must be %s or %s", o.Scope, ScopeSquashed, ScopeAllLayers)
+		return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	switch o.SearchArchives {
+	case "", ArchivesIndexed, ArchivesAll, ArchivesNone:
+	default:
+		errMsg := fmt.Sprintf("Invalid archive search %q, must be %s, %s or %s",
+			o.SearchArchives, ArchivesIndexed, ArchivesAll, ArchivesNone)
+		return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	for _, exclusion := range o.Exclusions {
+		// the paths are absolute from the root of the image or directory
+		if !strings.HasPrefix(exclusion, "/") && !strings.HasPrefix(exclusion, "**") ||
+			!doublestar.ValidatePattern(exclusion) {
+			errMsg := fmt.Sprintf("Invalid exclusion %q, must be an absolute glob, e.g. /usr/share/doc/** or **/test/**",
+				exclusion)
+			return cberr.NewError(cberr.ConfigErr, errMsg, nil)
+		}
+	}
+
+	return nil
+}
+
+// syftConfig will convert the option into the config of the syft catalogers for the scheme of the source.
+func (o CatalogOption) syftConfig(scheme source.Scheme) (cataloger.Config, error) {
+	cfg := cataloger.DefaultConfig()
+
+	if o.Scope != "" {
+		cfg.Search.Scope = source.ParseScope(o.Scope)
+	}
+
+	switch o.SearchArchives {
+	case ArchivesAll:
+		cfg.Search.IncludeIndexedArchives = true
+		cfg.Search.IncludeUnindexedArchives = true
+	case ArchivesNone:
+		cfg.Search.IncludeIndexedArchives = false
+		cfg.Search.IncludeUnindexedArchives = false
+	}
+
+	var included, excluded []string
+
+	for _, name := range o.Catalogers {
+		if strings.HasPrefix(name, excludedCatalogerPrefix) {
+			excluded = append(excluded, strings.TrimPrefix(name, excludedCatalogerPrefix))
+		} else {
+			included = append(included, name)
+		}
+	}
+
+	cfg.Catalogers = included
+	if len(excluded) == 0 {
+		return cfg, nil
+	}
+
+	// syft only supports the catalogers to enable, so enable all the ones not excluded
+	var catalogers []pkg.Cataloger
+
+	switch {
+	case len(included) == 0 && scheme == source.DirectoryScheme:
+		catalogers = cataloger.DirectoryCatalogers(cfg)
+	case len(included) == 0:
+		catalogers = cataloger.ImageCatalogers(cfg)
+	default:
+		catalogers = cataloger.AllCatalogers(cfg)
+	}
+
+	cfg.Catalogers = nil
+
+	for _, c := range catalogers {
+		if !matchesAny(c.Name(), excluded) {
+			cfg.Catalogers = append(cfg.Catalogers, c.Name())
+		}
+	}
+
+	if len(cfg.Catalogers) == 0 {
+		errMsg := fmt.Sprintf("No cataloger left once %s are excluded", strings.Join(excluded, ", "))
+		return cfg, cberr.NewError(cberr.ConfigErr, errMsg, nil)
+	}
+
+	return cfg, nil
+}
+
+// matchesAny reports if the name of the cataloger contains any of the parts.
+func matchesAny(name string, parts []string) bool {
+	for _, part := range parts {
+		if part != "" && strings.Contains(name, strings.TrimSuffix(part, "-cataloger")) {
+			return true
+		}
+	}
+
+	return false
+}
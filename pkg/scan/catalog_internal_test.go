@@ -0,0 +1,62 @@
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogOptionValidate(t *testing.T) {
+	require.NoError(t, CatalogOption{}.Validate())
+	require.NoError(t, DefaultCatalogOption().Validate())
+	require.NoError(t, CatalogOption{
+		Scope:          ScopeAllLayers,
+		Exclusions:     []string{"**/test/**", "/usr/share/doc/**", "/opt/{a,b}/*.jar"},
+		SearchArchives: ArchivesNone,
+	}.Validate())
+
+	require.Error(t, CatalogOption{Scope: "squash"}.Validate())
+	require.Error(t, CatalogOption{SearchArchives: "nested"}.Validate())
+	require.Error(t, CatalogOption{Exclusions: []string{"test/**"}}.Validate())
+	require.Error(t, CatalogOption{Exclusions: []string{"/opt/[a"}}.Validate())
+}
+
+func TestCatalogOptionIsDefault(t *testing.T) {
+	require.True(t, CatalogOption{}.IsDefault())
+	require.True(t, DefaultCatalogOption().IsDefault())
+	require.False(t, CatalogOption{Scope: ScopeAllLayers}.IsDefault())
+	require.False(t, CatalogOption{SearchArchives: ArchivesAll}.IsDefault())
+	require.False(t, CatalogOption{Catalogers: []string{"apk"}}.IsDefault())
+	require.False(t, CatalogOption{Exclusions: []string{"**/test/**"}}.IsDefault())
+}
+
+func TestCatalogOptionSyftConfig(t *testing.T) {
+	cfg, err := DefaultCatalogOption().syftConfig(source.ImageScheme)
+	require.NoError(t, err)
+	require.Equal(t, source.SquashedScope, cfg.Search.Scope)
+	require.True(t, cfg.Search.IncludeIndexedArchives)
+	require.False(t, cfg.Search.IncludeUnindexedArchives)
+	require.Empty(t, cfg.Catalogers)
+
+	cfg, err = CatalogOption{Scope: ScopeAllLayers, SearchArchives: ArchivesAll, Catalogers: []string{"apk", "python"}}.
+		syftConfig(source.ImageScheme)
+	require.NoError(t, err)
+	require.Equal(t, source.AllLayersScope, cfg.Search.Scope)
+	require.True(t, cfg.Search.IncludeUnindexedArchives)
+	require.Equal(t, []string{"apk", "python"}, cfg.Catalogers)
+
+	cfg, err = CatalogOption{SearchArchives: ArchivesNone, Catalogers: []string{"-java"}}.syftConfig(source.ImageScheme)
+	require.NoError(t, err)
+	require.False(t, cfg.Search.IncludeIndexedArchives)
+	require.NotEmpty(t, cfg.Catalogers)
+	require.Contains(t, cfg.Catalogers, "apkdb-cataloger")
+
+	for _, name := range cfg.Catalogers {
+		require.False(t, strings.Contains(name, "java"), name)
+	}
+
+	_, err = CatalogOption{Catalogers: []string{"-cataloger"}}.syftConfig(source.ImageScheme)
+	require.Error(t, err)
+}
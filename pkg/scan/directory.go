@@ -0,0 +1,215 @@
+package scan
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/anchore/syft/syft"
+	"github.com/anchore/syft/syft/source"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+)
+
+// DirectoryPrefix marks an input as a local directory instead of an image, e.g. dir:./build.
+const DirectoryPrefix = "dir:"
+
+const (
+	// directoryRepo is the repo of the synthetic tags of the directories
+	directoryRepo = "fs"
+	// directoryTagLength is the number of hex digits of the digest kept in the synthetic tag
+	directoryTagLength = 12
+)
+
+var invalidRepoChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// IsDirectoryInput reports if the input is a local directory, i.e. prefixed with dir:.
+func IsDirectoryInput(input string) bool {
+	return strings.HasPrefix(input, DirectoryPrefix)
+}
+
+// DirectoryPath will return the path of the directory input.
+func DirectoryPath(input string) string {
+	return strings.TrimPrefix(input, DirectoryPrefix)
+}
+
+// GenerateSBOMFromDirectory runs the directory through syft's catalogers and returns a populated SBOM of the found
+// packages. As a directory has no manifest, the digest is the sha256 of the packages found, so that the same content
+// gets the same synthetic tag, e.g. fs/app:0123456789ab
+func GenerateSBOMFromDirectory(path string, catalog CatalogOption) (*Bom, error) {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		errMsg := fmt.Sprintf("Failed to read directory %v", path)
+		if err == nil {
+			errMsg = fmt.Sprintf("Input %v is not a directory", path)
+		}
+
+		e := cberr.NewError(cberr.ImageLoadErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	cft, err := catalog.syftConfig(source.DirectoryScheme)
+	if err != nil {
+		return nil, err
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	name := directoryName(absPath)
+
+	theSource, err := source.NewFromDirectoryRootWithName(absPath, name)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to create directory source from input %v", path)
+
+		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	theSource.Exclusions = directoryExclusions(catalog.Exclusions)
+
+	theCatalog, _, linuxDistro, err := syft.CatalogPackages(&theSource, cft)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to catalog directory %v", path)
+
+		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	doc, err := bom.NewJSONDocument(theCatalog, theSource.Metadata, linuxDistro, cft.Search.Scope)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to parse the sbom for %v", path)
+		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	doc.Descriptor = &bom.JSONDescriptor{
+		Name:          "syft",
+		Version:       version.GetCurrentVersion().SyftVersion,
+		Configuration: catalog,
+	}
+
+	digest, err := directoryDigest(doc)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to compute the digest of %v", path)
+		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	fullTag := fmt.Sprintf("%s/%s:%s", directoryRepo, name, strings.TrimPrefix(digest, "sha256:")[:directoryTagLength])
+	if fullTag, err = addDefaultValuesToFullTag(fullTag); err != nil {
+		logrus.WithFields(logrus.Fields{"err": err, "tag": fullTag}).Error("fail formatting directory into tag")
+		return nil, err
+	}
+
+	logrus.WithField("fullTag", fullTag).Infof("SBOM generated successfully")
+
+	return &Bom{
+		FullTag:        fullTag,
+		ManifestDigest: digest,
+		Packages:       doc,
+	}, nil
+}
+
+// ExtractDataFromDirectory will generate the sbom of the directory input, the errors are published to the bus.
+// Once ctx is done, the result of the cataloging is not waited for
+func (s *Scanner) ExtractDataFromDirectory(ctx context.Context, input string, opts Option) (*Bom, bool) {
+	span := metrictool.StartSpan(metrictool.StageCatalog)
+	defer span.End()
+
+	var generatedBom *Bom
+
+	var errBom error
+
+	done := make(chan struct{})
+
+	go func() {
+		generatedBom, errBom = GenerateSBOMFromDirectory(DirectoryPath(input), opts.Catalog)
+		close(done)
+	}()
+
+	// the cataloging does not support cancellation, so stop waiting for it once ctx is done
+	select {
+	case <-ctx.Done():
+		publishInterrupted(input, ctx.Err())
+		return nil, true
+	case <-done:
+	}
+
+	span.SetError(errBom)
+
+	if errBom != nil {
+		bus.Publish(bus.NewErrorEvent(errBom))
+		return nil, true
+	}
+
+	metrictool.Add(metrictool.CounterPackages, int64(len(generatedBom.Packages.Artifacts)))
+
+	return generatedBom, false
+}
+
+// directoryName will return the base name of the directory, usable as the repo of a tag.
+func directoryName(path string) string {
+	name := invalidRepoChars.ReplaceAllString(strings.ToLower(filepath.Base(path)), "-")
+	name = strings.Trim(name, "._-")
+
+	if name == "" {
+		return "root"
+	}
+
+	return name
+}
+
+// directoryExclusions will convert the exclusions into the globs relative to the root of the directory,
+// as required by syft, e.g. /vendor/** into ./vendor/**
+func directoryExclusions(exclusions []string) []string {
+	converted := make([]string, 0, len(exclusions))
+
+	for _, exclusion := range exclusions {
+		switch {
+		case strings.HasPrefix(exclusion, "**/"):
+		case strings.HasPrefix(exclusion, "/"):
+			exclusion = "." + exclusion
+		default:
+			exclusion = "./" + exclusion
+		}
+
+		converted = append(converted, exclusion)
+	}
+
+	return converted
+}
+
+// directoryDigest will compute the sha256 of the packages of the document, which stands for the image id.
+func directoryDigest(doc bom.JSONDocument) (string, error) {
+	data, err := json.Marshal(doc.Artifacts)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(data)
+
+	return "sha256:" + hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,71 @@
+package scan
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testRequirements = "requests==2.19.0\nurllib3==1.23\n"
+
+func writeTestDirectory(t *testing.T, name string) string {
+	dir := filepath.Join(t.TempDir(), name)
+
+	for _, sub := range []string{"app", "test"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, sub), 0o755))
+		require.NoError(t, ioutil.WriteFile(
+			filepath.Join(dir, sub, "requirements.txt"), []byte(testRequirements), 0o600))
+	}
+
+	return dir
+}
+
+func TestGenerateSBOMFromDirectory(t *testing.T) {
+	dir := writeTestDirectory(t, "My App")
+
+	generated, err := GenerateSBOMFromDirectory(dir, DefaultCatalogOption())
+	require.NoError(t, err)
+	require.Equal(t, "directory", generated.Packages.Source.Type)
+	require.Len(t, generated.Packages.Artifacts, 4)
+	require.NotNil(t, generated.Packages.Descriptor)
+	require.True(t, strings.HasPrefix(generated.ManifestDigest, "sha256:"))
+	require.Equal(t, "docker.io/fs/my-app:"+generated.ManifestDigest[len("sha256:"):][:directoryTagLength],
+		generated.FullTag)
+
+	// the same content gets the same digest
+	again, err := GenerateSBOMFromDirectory(dir, DefaultCatalogOption())
+	require.NoError(t, err)
+	require.Equal(t, generated.ManifestDigest, again.ManifestDigest)
+
+	excluded, err := GenerateSBOMFromDirectory(dir, CatalogOption{Exclusions: []string{"/test/**"}})
+	require.NoError(t, err)
+	require.Len(t, excluded.Packages.Artifacts, 2)
+	require.NotEqual(t, generated.ManifestDigest, excluded.ManifestDigest)
+
+	for _, artifact := range excluded.Packages.Artifacts {
+		for _, location := range artifact.Locations {
+			require.False(t, strings.HasPrefix(location.RealPath, "/test/"), location.RealPath)
+		}
+	}
+
+	_, err = GenerateSBOMFromDirectory(filepath.Join(dir, "app", "requirements.txt"), DefaultCatalogOption())
+	require.Error(t, err)
+
+	_, err = GenerateSBOMFromDirectory(filepath.Join(dir, "missing"), DefaultCatalogOption())
+	require.Error(t, err)
+}
+
+func TestDirectoryInput(t *testing.T) {
+	require.True(t, IsDirectoryInput("dir:./build"))
+	require.False(t, IsDirectoryInput("alpine:3.13"))
+	require.Equal(t, "./build", DirectoryPath("dir:./build"))
+
+	require.Equal(t, "root", directoryName("/"))
+	require.Equal(t, "monorepo_v2", directoryName("/src/MonoRepo_v2"))
+	require.Equal(t, []string{"./vendor/**", "**/test/**", "./**.md"},
+		directoryExclusions([]string{"/vendor/**", "**/test/**", "**.md"}))
+}
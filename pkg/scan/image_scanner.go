@@ -1,10 +1,12 @@
 package scan
 
 import (
+	"context"
 	"fmt"
 	"github.com/anchore/stereoscope/pkg/image"
 	"github.com/sirupsen/logrus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
 	progress "github.com/wagoodman/go-progress"
@@ -21,7 +23,17 @@ func NewScanner() *Scanner {
 // GenerateSBOM is a wrapper around scan.GenerateSBOMFromImage
 func (s *Scanner) GenerateSBOM(img *image.Image, userInput string, opts Option) (*Bom, error) {
 	// Note: progress and events are handled by syft internally so we don't raise any events here
-	return GenerateSBOMFromImage(img, userInput, opts.FullTag)
+	span := metrictool.StartSpan(metrictool.StageCatalog)
+	defer span.End()
+
+	generatedBom, err := GenerateSBOMFromImage(img, userInput, opts.FullTag, opts.Catalog)
+	span.SetError(err)
+
+	if generatedBom != nil {
+		metrictool.Add(metrictool.CounterPackages, int64(len(generatedBom.Packages.Artifacts)))
+	}
+
+	return generatedBom, err
 }
 
 // GenerateLayersAndFiles is a wrapper around scan.GenerateLayersAndFileData
@@ -39,21 +51,43 @@ func (s *Scanner) GenerateLayersAndFiles(img *image.Image, _ string, _ Option) (
 	bus.Publish(bus.NewEvent(bus.NewCollectLayers, value, false))
 	defer prog.SetCompleted()
 
+	span := metrictool.StartSpan(metrictool.StageCollectLayers)
+	defer span.End()
+
 	foundLayers, err := GenerateLayersAndFileData(img)
 	if err != nil {
 		stage.Current = "failed"
+		span.SetError(err)
 		return nil, err
 	}
 
+	metrictool.Add(metrictool.CounterLayers, int64(len(foundLayers)))
+	for _, layer := range foundLayers {
+		metrictool.Add(metrictool.CounterFiles, int64(len(layer.Files)))
+	}
+
 	stage.Current = fmt.Sprintf("%d layers", len(foundLayers))
 	return foundLayers, nil
 }
 
-func (s *Scanner) ExtractDataFromImage(input string, opts Option) (*Bom, []layers.Layer, bool) {
+// ExtractDataFromImage will load the image and generate its sbom and layers, the errors are published to the bus.
+// Once ctx is done, the image pull is aborted and the temporary files are cleaned up
+func (s *Scanner) ExtractDataFromImage(ctx context.Context, input string, opts Option) (*Bom, []layers.Layer, bool) {
 	var msg string
 	registryHandler := NewRegistryHandler()
 
-	img, err := registryHandler.LoadImage(input, opts)
+	span := metrictool.StartSpan(metrictool.StagePull)
+	img, err := registryHandler.LoadImage(ctx, input, opts)
+	span.SetError(err)
+	span.End()
+
+	if ctx.Err() != nil {
+		// the pull might be interrupted half way, clean up the temporary files left
+		Cleanup()
+		publishInterrupted(input, ctx.Err())
+		return nil, nil, true
+	}
+
 	if err != nil {
 		msg := fmt.Sprintf("Failed to pull image for input %s", input)
 		e := cberr.NewError(cberr.ImageLoadErr, msg, err)
@@ -61,12 +95,16 @@ func (s *Scanner) ExtractDataFromImage(input string, opts Option) (*Bom, []layer
 		logrus.Errorln(e)
 		return nil, nil, true
 	}
-	defer func() {
-		if err := img.Cleanup(); err != nil {
-			logrus.WithError(err).Errorf("failed to clean up files for image [%s]", input)
-		}
-		Cleanup()
-	}()
+	var cleanupOnce sync.Once
+	cleanupImage := func() {
+		cleanupOnce.Do(func() {
+			if err := img.Cleanup(); err != nil {
+				logrus.WithError(err).Errorf("failed to clean up files for image [%s]", input)
+			}
+			Cleanup()
+		})
+	}
+	defer cleanupImage()
 
 	var generatedBom *Bom
 	var imgLayers []layers.Layer
@@ -84,7 +122,23 @@ func (s *Scanner) ExtractDataFromImage(input string, opts Option) (*Bom, []layer
 		wg.Done()
 	}()
 
-	wg.Wait()
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	// the cataloging does not support cancellation, so the image files it reads are only cleaned up once it is done,
+	// and before the interruption is published as the command exits on it
+	select {
+	case <-ctx.Done():
+		logrus.Infof("Waiting for the cataloging of %s to finish before cleaning up", input)
+		<-done
+		cleanupImage()
+		publishInterrupted(input, ctx.Err())
+		return nil, nil, true
+	case <-done:
+	}
 
 	if errBom != nil {
 		bus.Publish(bus.NewErrorEvent(errBom))
@@ -114,3 +168,10 @@ func (s *Scanner) ExtractDataFromImage(input string, opts Option) (*Bom, []layer
 
 	return generatedBom, imgLayers, false
 }
+
+func publishInterrupted(input string, err error) {
+	msg := fmt.Sprintf("Interrupted while extracting data from %s", input)
+	e := cberr.NewError(cberr.InterruptedErr, msg, err)
+	bus.Publish(bus.NewErrorEvent(e))
+	logrus.Errorln(e)
+}
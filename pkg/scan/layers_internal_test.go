@@ -1,6 +1,7 @@
 package scan
 
 import (
+	"context"
 	"fmt"
 	"github.com/smartystreets/goconvey/convey"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
@@ -13,7 +14,7 @@ func TestGenerateLayers(t *testing.T) {
 			registryHandler := NewRegistryHandler()
 			input := testImageAlpineTar
 
-			img, err := registryHandler.LoadImage(input, Option{})
+			img, err := registryHandler.LoadImage(context.Background(), input, Option{})
 			convey.So(err, convey.ShouldBeNil)
 			imageLayers, err := GenerateLayersAndFileData(img)
 			convey.So(err, convey.ShouldBeNil)
@@ -51,7 +52,7 @@ func TestGenerateLayers(t *testing.T) {
 			registryHandler := NewRegistryHandler()
 			input := testImageLayersAndFilesTar
 
-			img, err := registryHandler.LoadImage(input, Option{})
+			img, err := registryHandler.LoadImage(context.Background(), input, Option{})
 			convey.So(err, convey.ShouldBeNil)
 
 			imageLayers, err := GenerateLayersAndFileData(img)
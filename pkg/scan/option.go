@@ -2,6 +2,9 @@ package scan
 
 import (
 	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/logtool"
 )
 
 const (
@@ -22,10 +25,28 @@ type Option struct {
 	ShouldCleanup bool
 	// FullTag is the tag set to override in the image
 	FullTag string
+	// ManifestDigest is the manifest digest set to override in an imported sbom
+	ManifestDigest string
 	// Timeout is the duration (second) for the scan process
 	Timeout int
 
 	DockerInsecureSkipTLSVerify bool
+
+	// Catalog is the option of the cataloging of the packages
+	Catalog CatalogOption
+}
+
+// Authenticator returns the authenticator of the credential for the registry, or nil without credential
+// so that the credentials of the docker config are used like for the image pull.
+func (o Option) Authenticator() authn.Authenticator {
+	if o.Credential == "" {
+		return nil
+	}
+
+	username, password := o.parseAuth()
+	logtool.AddSecret(password)
+
+	return &authn.Basic{Username: username, Password: password}
 }
 
 func (o Option) parseAuth() (username string, password string) {
@@ -0,0 +1,362 @@
+package scan
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/docker/archive"
+	"github.com/containers/image/v5/docker/daemon"
+	"github.com/containers/image/v5/docker/reference"
+	ociarchive "github.com/containers/image/v5/oci/archive"
+	ocilayout "github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/types"
+	"github.com/mitchellh/go-homedir"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+)
+
+// The explicit schemes of the image inputs, e.g. oci-archive:path/to/image.tar.
+const (
+	SchemeDocker        = "docker"
+	SchemeDockerDaemon  = "docker-daemon"
+	SchemePodman        = "podman"
+	SchemeOCIDir        = "oci-dir"
+	SchemeOCIArchive    = "oci-archive"
+	SchemeDockerArchive = "docker-archive"
+	SchemeRegistry      = "registry"
+)
+
+const (
+	schemeSeparator = ":"
+	// indexSelectorPrefix marks the selector of an image by its index in the archive, e.g. images.tar:@1
+	indexSelectorPrefix = "@"
+	ociIndexFile        = "index.json"
+)
+
+var imageSchemes = map[string]image.Source{
+	SchemeDocker:        image.DockerDaemonSource,
+	SchemeDockerDaemon:  image.DockerDaemonSource,
+	SchemePodman:        image.PodmanDaemonSource,
+	SchemeOCIDir:        image.OciDirectorySource,
+	SchemeOCIArchive:    image.OciTarballSource,
+	SchemeDockerArchive: image.DockerTarballSource,
+	SchemeRegistry:      image.OciRegistrySource,
+}
+
+// ImageReference is an image input resolved into the source the image is read from, both the image id lookup
+// and the image load use it so that they read the same image.
+type ImageReference struct {
+	// Input is the original input
+	Input string
+	// Source is where the image is read from
+	Source image.Source
+	// Location is the image reference for a daemon or a registry, or the path of an archive or a layout
+	Location string
+	// Selector picks an image inside a multi-image archive or layout, either a tag or @index
+	Selector string
+	// Explicit is whether the source is set by a scheme of the input
+	Explicit bool
+}
+
+// ResolveImageReference will resolve the input into its source. The input can start with a scheme, e.g. oci-dir:,
+// otherwise the source is detected from the local path or defaults to the docker daemon or the registry.
+// An archive or a layout holding several images can be followed by the tag or @index of the image to select,
+// e.g. docker-archive:images.tar:alpine:3.13 or oci-dir:layout:@1
+func ResolveImageReference(input string) (ImageReference, error) {
+	ref := ImageReference{Input: input, Location: input}
+
+	if parts := strings.SplitN(input, schemeSeparator, 2); len(parts) == 2 && isScheme(parts[0], parts[1]) {
+		ref.Source = imageSchemes[strings.ToLower(parts[0])]
+		ref.Location = strings.TrimPrefix(parts[1], "//")
+		ref.Explicit = true
+	}
+
+	switch {
+	case ref.Explicit && ref.IsArchive():
+		// the shell does not expand the home dir after a scheme
+		location, err := homedir.Expand(ref.Location)
+		if err != nil {
+			return ref, invalidReference(input, err)
+		}
+
+		ref.Location, ref.Selector = splitSelector(location)
+	case ref.Explicit:
+	default:
+		location, selector := splitSelector(input)
+
+		src, err := image.DetectSourceFromPath(location)
+		if err == nil && src != image.UnknownSource {
+			ref.Source, ref.Location, ref.Selector = src, location, selector
+			break
+		}
+
+		// a path followed by a selector might be an image reference, e.g. alpine:3.13 with an alpine directory
+		if _, statErr := os.Stat(location); err == nil && statErr == nil && selector == "" {
+			return ref, invalidReference(input, errors.New("input is not a valid archive or layout"))
+		}
+
+		// the input is not a local path, so the image is pulled
+		ref.Source = image.DetermineDefaultImagePullSource(input)
+		if ref.Source == image.UnknownSource {
+			return ref, invalidReference(input, err)
+		}
+	}
+
+	if ref.Location == "" {
+		return ref, invalidReference(input, errors.New("the image location is empty"))
+	}
+
+	if ref.Selector != "" {
+		if ref.Source == image.SingularitySource {
+			return ref, invalidReference(input, errors.New("singularity images can not be selected"))
+		}
+
+		if _, err := ref.selectedIndex(); err != nil {
+			return ref, invalidReference(input, err)
+		}
+	}
+
+	return ref, nil
+}
+
+// IsArchive reports if the image is read from a local archive or layout.
+func (r ImageReference) IsArchive() bool {
+	switch r.Source {
+	case image.DockerTarballSource, image.OciTarballSource, image.OciDirectorySource, image.SingularitySource:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContainersReference will convert the reference into a reference of containers/image, the image id lookup reads
+// the config of the image through it.
+func (r ImageReference) ContainersReference() (types.ImageReference, error) {
+	switch r.Source {
+	case image.DockerDaemonSource, image.OciRegistrySource:
+		named, err := reference.ParseNormalizedNamed(r.Location)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Source == image.OciRegistrySource {
+			return docker.NewReference(reference.TagNameOnly(named))
+		}
+
+		return daemon.NewReference("", reference.TagNameOnly(named))
+	case image.DockerTarballSource:
+		index, err := r.selectedIndex()
+		if err != nil {
+			return nil, err
+		}
+
+		if index >= 0 {
+			return archive.NewIndexReference(r.Location, index)
+		}
+
+		tagged, err := r.selectedTag()
+		if err != nil {
+			return nil, err
+		}
+
+		return archive.NewReference(r.Location, tagged)
+	case image.OciDirectorySource, image.OciTarballSource:
+		name, err := r.ociName()
+		if err != nil {
+			return nil, err
+		}
+
+		if r.Source == image.OciDirectorySource {
+			return ocilayout.NewReference(r.Location, name)
+		}
+
+		return ociarchive.NewReference(r.Location, name)
+	default:
+		return nil, fmt.Errorf("the image source %s is not supported for reading the image config", r.Source)
+	}
+}
+
+// Tags will return the tag of the selected image, if it is selected by a tag of a docker archive. The names
+// of the OCI images are not required to be image references.
+func (r ImageReference) Tags() []string {
+	if r.Source != image.DockerTarballSource {
+		return nil
+	}
+
+	if tagged, err := r.selectedTag(); err == nil && tagged != nil {
+		return []string{tagged.String()}
+	}
+
+	return nil
+}
+
+// selectedIndex will return the index of the selected image, or -1 if it is not selected by index.
+func (r ImageReference) selectedIndex() (int, error) {
+	if !strings.HasPrefix(r.Selector, indexSelectorPrefix) {
+		return -1, nil
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(r.Selector, indexSelectorPrefix))
+	if err != nil || index < 0 {
+		return -1, fmt.Errorf("invalid image index %q, must be @0, @1, ...", r.Selector)
+	}
+
+	return index, nil
+}
+
+// selectedTag will return the tag of the selected image, or nil if it is not selected by a docker tag.
+func (r ImageReference) selectedTag() (reference.NamedTagged, error) {
+	if r.Selector == "" || strings.HasPrefix(r.Selector, indexSelectorPrefix) {
+		return nil, nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(r.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image tag %q: %w", r.Selector, err)
+	}
+
+	tagged, ok := reference.TagNameOnly(named).(reference.NamedTagged)
+	if !ok {
+		return nil, fmt.Errorf("the image %q must be selected by a tag", r.Selector)
+	}
+
+	return tagged, nil
+}
+
+// ociName will return the ref name of the selected image in the OCI index, an image selected by index must be named.
+func (r ImageReference) ociName() (string, error) {
+	index, err := r.selectedIndex()
+	if err != nil || index < 0 {
+		return r.Selector, err
+	}
+
+	ociIndex, err := r.readOCIIndex()
+	if err != nil {
+		return "", err
+	}
+
+	if index >= len(ociIndex.Manifests) {
+		return "", fmt.Errorf("no image %s in %s holding %d images", r.Selector, r.Location, len(ociIndex.Manifests))
+	}
+
+	name := ociIndex.Manifests[index].Annotations[imgspecv1.AnnotationRefName]
+	if name == "" {
+		return "", fmt.Errorf("image %s of %s has no %s annotation, it can not be selected",
+			r.Selector, r.Location, imgspecv1.AnnotationRefName)
+	}
+
+	return name, nil
+}
+
+// readOCIIndex will read the index of the OCI layout, or of the OCI archive.
+func (r ImageReference) readOCIIndex() (*imgspecv1.Index, error) {
+	var data []byte
+
+	var err error
+
+	if r.Source == image.OciDirectorySource {
+		data, err = ioutil.ReadFile(filepath.Join(r.Location, ociIndexFile))
+	} else {
+		data, err = readTarEntry(r.Location, ociIndexFile)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var index imgspecv1.Index
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("failed to parse the OCI index of %s: %w", r.Location, err)
+	}
+
+	return &index, nil
+}
+
+// isScheme reports if the prefix of the input is a scheme. The docker, podman and registry schemes are also names
+// of official images, so they are schemes only if the rest of the input is not a bare tag, e.g. docker:24 is the
+// docker image while docker:alpine:3.13 and docker://alpine are the alpine image of the docker daemon.
+func isScheme(prefix, rest string) bool {
+	scheme := strings.ToLower(prefix)
+	if _, ok := imageSchemes[scheme]; !ok {
+		return false
+	}
+
+	switch scheme {
+	case SchemeDocker, SchemePodman, SchemeRegistry:
+		return strings.ContainsAny(rest, "/:@")
+	default:
+		return true
+	}
+}
+
+// isArchiveScheme reports if the scheme is the one of a local archive or layout.
+func isArchiveScheme(scheme string) bool {
+	switch strings.ToLower(scheme) {
+	case SchemeOCIDir, SchemeOCIArchive, SchemeDockerArchive:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitSelector will split the path of the archive or layout from the selector of the image,
+// the path is the shortest prefix existing, as both the path and the tag can contain colons.
+func splitSelector(location string) (string, string) {
+	if _, err := os.Stat(location); err == nil {
+		return location, ""
+	}
+
+	for i := 0; i < len(location); i++ {
+		if location[i] != schemeSeparator[0] || i == 0 {
+			continue
+		}
+
+		if _, err := os.Stat(location[:i]); err == nil {
+			return location[:i], location[i+1:]
+		}
+	}
+
+	return location, ""
+}
+
+// readTarEntry will read the content of the entry of the tar file.
+func readTarEntry(tarPath, name string) ([]byte, error) {
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = file.Close() }()
+
+	reader := tar.NewReader(file)
+
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s in %s", name, tarPath)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if path.Clean(header.Name) == name {
+			return ioutil.ReadAll(reader)
+		}
+	}
+}
+
+func invalidReference(input string, err error) error {
+	return cberr.NewError(cberr.SBOMGenerationErr, fmt.Sprintf("The input %s is not a valid scan source", input), err)
+}
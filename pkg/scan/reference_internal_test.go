@@ -0,0 +1,225 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testTagA = "example.com/app:1.0"
+	testTagB = "example.com/app:2.0"
+)
+
+func randomImages(t *testing.T) map[string]v1.Image {
+	images := make(map[string]v1.Image)
+
+	for _, tag := range []string{testTagA, testTagB} {
+		img, err := random.Image(256, 1)
+		require.NoError(t, err)
+
+		images[tag] = img
+	}
+
+	return images
+}
+
+func configDigest(t *testing.T, img v1.Image) string {
+	digest, err := img.ConfigName()
+	require.NoError(t, err)
+
+	return digest.String()
+}
+
+// writeDockerArchive will write the images to a docker archive, and return their tags in the order of the archive.
+func writeDockerArchive(t *testing.T, images map[string]v1.Image) (string, []string) {
+	path := filepath.Join(t.TempDir(), "images.tar")
+
+	refs := make(map[name.Tag]v1.Image)
+
+	for tag, img := range images {
+		ref, err := name.NewTag(tag)
+		require.NoError(t, err)
+
+		refs[ref] = img
+	}
+
+	require.NoError(t, tarball.MultiWriteToFile(path, refs))
+
+	manifest, err := tarball.LoadManifest(func() (io.ReadCloser, error) { return os.Open(path) })
+	require.NoError(t, err)
+
+	var tags []string
+	for _, entry := range manifest {
+		tags = append(tags, entry.RepoTags[0])
+	}
+
+	return path, tags
+}
+
+// writeOCILayout will write the images to an OCI layout with the OCI media types, the names are the ref name
+// annotations of the images.
+func writeOCILayout(t *testing.T, images []v1.Image, names ...string) string {
+	dir := filepath.Join(t.TempDir(), "layout")
+
+	path, err := layout.Write(dir, empty.Index)
+	require.NoError(t, err)
+
+	for i, img := range images {
+		var opts []layout.Option
+		if i < len(names) {
+			opts = append(opts, layout.WithAnnotations(map[string]string{imgspecv1.AnnotationRefName: names[i]}))
+		}
+
+		img = mutate.ConfigMediaType(mutate.MediaType(img, types.OCIManifestSchema1), types.OCIConfigJSON)
+		require.NoError(t, path.AppendImage(img, opts...))
+	}
+
+	return dir
+}
+
+func TestResolveImageReference(t *testing.T) {
+	images := randomImages(t)
+	archivePath, _ := writeDockerArchive(t, images)
+	layoutDir := writeOCILayout(t, []v1.Image{images[testTagA]})
+
+	tests := []struct {
+		input    string
+		source   image.Source
+		location string
+		selector string
+	}{
+		{"docker-archive:" + archivePath + ":" + testTagB, image.DockerTarballSource, archivePath, testTagB},
+		{archivePath + ":@1", image.DockerTarballSource, archivePath, "@1"},
+		{archivePath, image.DockerTarballSource, archivePath, ""},
+		{"oci-dir:" + layoutDir, image.OciDirectorySource, layoutDir, ""},
+		{layoutDir + ":@0", image.OciDirectorySource, layoutDir, "@0"},
+		{"registry:alpine:3.13", image.OciRegistrySource, "alpine:3.13", ""},
+		{"docker-daemon:alpine", image.DockerDaemonSource, "alpine", ""},
+		{"podman:alpine:3.13", image.PodmanDaemonSource, "alpine:3.13", ""},
+		{"docker://alpine", image.DockerDaemonSource, "alpine", ""},
+		{"registry:library/alpine", image.OciRegistrySource, "library/alpine", ""},
+	}
+
+	for _, tt := range tests {
+		ref, err := ResolveImageReference(tt.input)
+		require.NoError(t, err, tt.input)
+		require.Equal(t, tt.source, ref.Source, tt.input)
+		require.Equal(t, tt.location, ref.Location, tt.input)
+		require.Equal(t, tt.selector, ref.Selector, tt.input)
+	}
+
+	// the image references, also of the official images named after a scheme, are pulled from the default source
+	for _, input := range []string{"alpine:3.13", "docker:24", "registry:2", "podman:latest"} {
+		ref, err := ResolveImageReference(input)
+		require.NoError(t, err, input)
+		require.False(t, ref.Explicit, input)
+		require.Equal(t, input, ref.Location, input)
+	}
+
+	for _, input := range []string{
+		"docker-archive:" + archivePath + ":@first",
+		"oci-archive:",
+		"foo:bar:baz",
+	} {
+		_, err := ResolveImageReference(input)
+		require.Error(t, err, input)
+	}
+}
+
+func TestContainersReference(t *testing.T) {
+	images := randomImages(t)
+	archivePath, tags := writeDockerArchive(t, images)
+	layoutDir := writeOCILayout(t, []v1.Image{images[testTagA], images[testTagB]}, "app-a")
+
+	// the image id lookup reads the same image as the one selected
+	ref, err := ResolveImageReference(archivePath + ":@1")
+	require.NoError(t, err)
+
+	imageRef, err := ref.ContainersReference()
+	require.NoError(t, err)
+
+	src, err := imageRef.NewImageSource(context.Background(), imageSystemContext())
+	require.NoError(t, err)
+
+	defer func() { _ = src.Close() }()
+
+	rawManifest, _, err := src.GetManifest(context.Background(), nil)
+	require.NoError(t, err)
+
+	manifest, err := v1.ParseManifest(bytes.NewReader(rawManifest))
+	require.NoError(t, err)
+	require.Equal(t, configDigest(t, images[tags[1]]), manifest.Config.Digest.String())
+
+	ref, err = ResolveImageReference("oci-dir:" + layoutDir + ":@0")
+	require.NoError(t, err)
+
+	imageRef, err = ref.ContainersReference()
+	require.NoError(t, err)
+	require.Equal(t, layoutDir+":app-a", imageRef.StringWithinTransport())
+
+	// the unnamed images of a layout can not be selected by index
+	ref, err = ResolveImageReference("oci-dir:" + layoutDir + ":@1")
+	require.NoError(t, err)
+
+	_, err = ref.ContainersReference()
+	require.Error(t, err)
+
+	ref, err = ResolveImageReference("docker-daemon:alpine")
+	require.NoError(t, err)
+
+	imageRef, err = ref.ContainersReference()
+	require.NoError(t, err)
+	require.Equal(t, "alpine:latest", imageRef.StringWithinTransport())
+}
+
+func TestLoadSelectedImage(t *testing.T) {
+	defer Cleanup()
+
+	images := randomImages(t)
+	archivePath, tags := writeDockerArchive(t, images)
+	layoutDir := writeOCILayout(t, []v1.Image{images[testTagA], images[testTagB]}, "app-a", "app-b")
+	handler := NewRegistryHandler()
+
+	img, err := handler.LoadImage(context.Background(), "docker-archive:"+archivePath+":"+testTagB, Option{})
+	require.NoError(t, err)
+	require.Equal(t, configDigest(t, images[testTagB]), img.Metadata.ID)
+	require.Len(t, img.Metadata.Tags, 1)
+	require.Equal(t, testTagB, img.Metadata.Tags[0].String())
+
+	img, err = handler.LoadImage(context.Background(), archivePath+":@0", Option{})
+	require.NoError(t, err)
+	require.Equal(t, configDigest(t, images[tags[0]]), img.Metadata.ID)
+
+	img, err = handler.LoadImage(context.Background(), "oci-dir:"+layoutDir+":@1", Option{})
+	require.NoError(t, err)
+	require.Equal(t, configDigest(t, images[testTagB]), img.Metadata.ID)
+
+	// several images must be selected
+	_, err = handler.LoadImage(context.Background(), layoutDir, Option{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), ":@<index>")
+}
+
+func TestGenerateFullTagFromArchive(t *testing.T) {
+	layoutDir := writeOCILayout(t, nil)
+
+	require.Equal(t, "docker.io/library/layout:"+digest,
+		generateFullTagFromOriginInput("oci-dir:"+layoutDir+":@0", "sha256:"+digest))
+	require.Equal(t, "docker.io/library/layout:"+digest, generateFullTagFromOriginInput(layoutDir, "sha256:"+digest))
+}
@@ -3,9 +3,19 @@ package scan
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
 	"github.com/anchore/stereoscope"
 	"github.com/anchore/stereoscope/pkg/image"
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker/archive"
+	ocilayout "github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/signature"
+	"github.com/containers/image/v5/types"
 	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/logtool"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 )
 
@@ -27,19 +37,22 @@ func NewRegistryHandler() RegistryHandler {
 // LoadImage parses the provided input and attempts to load an image from it
 // If successful, the first returned parameter will be populated and ready to use in scanning methods
 // Sharing the returned image for reading is expected, shared writing is not supported
-func (h *RegistryHandler) LoadImage(input string, opts Option) (*image.Image, error) {
+// The pull is aborted once ctx is done
+func (h *RegistryHandler) LoadImage(ctx context.Context, input string, opts Option) (*image.Image, error) {
 	logrus.WithField("input", input).Info("Loading image from source")
-	src, err := parseSourceFromRawRef(input)
+	ref, err := ResolveImageReference(input)
 	if err != nil {
 		logrus.WithField("input", input).WithError(err).Error("Invalid source for input, cannot load image")
 		return nil, err
 	}
+	src := ref.Source
 	logrus.Debugf("Detected source is (%v)", src)
 
 	stereoPullOptions := getImageLoadOptions()
 	if opts.Credential != "" {
 		logrus.Debug("Credentials passed in options, will use them to authenticate with registry")
 		user, pass := opts.parseAuth()
+		logtool.AddSecret(pass)
 		stereoPullOptions = append(stereoPullOptions, stereoscope.WithCredentials(image.RegistryCredentials{
 			Authority: "",
 			Username:  user,
@@ -52,7 +65,7 @@ func (h *RegistryHandler) LoadImage(input string, opts Option) (*image.Image, er
 	if opts.BypassDockerDaemon && (src == image.DockerDaemonSource || src == image.PodmanDaemonSource) {
 		logrus.WithField("input", input).Debugf("BypassDockerDaemon enabled and source is (%v), attempting to pull from registry instead", src)
 		// Attempt to load directly from docker. If that fails; we fallback to loading from the daemon for backwards compatibility
-		if img, err := stereoscope.GetImageFromSource(context.Background(), input, image.OciRegistrySource, stereoPullOptions...); err != nil {
+		if img, err := stereoscope.GetImageFromSource(ctx, ref.Location, image.OciRegistrySource, stereoPullOptions...); err != nil {
 			logrus.WithError(err).Warnf("Failed to pull directly from registry for input (%s); will fallback to local daemon", input)
 		} else {
 			logrus.WithFields(logrus.Fields{"original-input": input, "detected-source": src, "actual-source": image.OciRegistrySource}).
@@ -61,9 +74,22 @@ func (h *RegistryHandler) LoadImage(input string, opts Option) (*image.Image, er
 		}
 	}
 
-	img, err := stereoscope.GetImageFromSource(context.Background(), input, src, stereoPullOptions...)
+	var img *image.Image
+	if ref.Selector != "" {
+		img, err = loadSelectedImage(ctx, ref, stereoPullOptions)
+	} else {
+		img, err = stereoscope.GetImageFromSource(ctx, ref.Location, src, stereoPullOptions...)
+	}
+
 	if err != nil {
 		logrus.WithField("input", input).Error("Failed to load image from source")
+
+		if ref.IsArchive() && ref.Selector == "" {
+			msg := fmt.Sprintf("Failed to load %s, if it holds several images select one with %s:<tag> or %s:@<index>",
+				ref.Location, input, input)
+			return nil, cberr.NewError(cberr.ImageLoadErr, msg, err)
+		}
+
 		return nil, err
 	}
 
@@ -79,28 +105,75 @@ func (h *RegistryHandler) LoadImage(input string, opts Option) (*image.Image, er
 	return img, nil
 }
 
-func parseSourceFromRawRef(reference string) (image.Source, error) {
-	// Try to determinate if the source is an archive
-	inputSrc, err := image.DetectSourceFromPath(reference)
-	if err == nil {
-		if inputSrc == image.UnknownSource || inputSrc == image.OciDirectorySource {
-			err = fmt.Errorf("input is not a valid archive source or image pull source")
-		} else {
-			return inputSrc, nil
+// loadSelectedImage will load the image selected inside a multi-image archive or layout, which stereoscope does
+// not support, so the image is copied alone to a temporary archive or layout of the same format first. A conversion
+// would rewrite the config, and so change the image id.
+func loadSelectedImage(ctx context.Context, ref ImageReference, opts []stereoscope.Option) (*image.Image, error) {
+	srcRef, err := ref.ContainersReference()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := ioutil.TempDir("", "cbctl-selected-image-")
+	if err != nil {
+		return nil, err
+	}
+
+	// the layers are extracted by stereoscope once the image is read, so the layout is not needed afterwards
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			logrus.WithError(err).Warnf("failed to clean up the selected image of %s", ref.Input)
 		}
+	}()
+
+	var destRef types.ImageReference
+
+	destSrc := image.OciDirectorySource
+	destLocation := dir
+
+	if ref.Source == image.DockerTarballSource {
+		destSrc = image.DockerTarballSource
+		destLocation = filepath.Join(dir, "image.tar")
+		destRef, err = archive.NewReference(destLocation, nil)
+	} else {
+		destRef, err = ocilayout.NewReference(dir, "")
 	}
 
-	// If the source isn't an archive, try to detect the image pull source
-	inputSrc = image.DetermineDefaultImagePullSource(reference)
+	if err != nil {
+		return nil, err
+	}
 
-	if inputSrc == image.UnknownSource {
-		e := cberr.NewError(cberr.SBOMGenerationErr, "The input is not a valid scan source", err)
-		logrus.Errorln(e.Error())
+	policyContext, err := signature.NewPolicyContext(&signature.Policy{
+		Default: []signature.PolicyRequirement{signature.NewPRInsecureAcceptAnything()},
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		return image.UnknownSource, e
+	defer func() { _ = policyContext.Destroy() }()
+
+	logrus.WithFields(logrus.Fields{"input": ref.Input, "selector": ref.Selector}).Debug("Copying the selected image")
+
+	copyOpts := &copy.Options{SourceCtx: imageSystemContext(), PreserveDigests: true}
+	if _, err := copy.Image(ctx, policyContext, destRef, srcRef, copyOpts); err != nil {
+		return nil, fmt.Errorf("failed to select image %s of %s: %w", ref.Selector, ref.Location, err)
+	}
+
+	if tags := ref.Tags(); len(tags) > 0 {
+		opts = append(opts, stereoscope.WithAdditionalMetadata(image.WithTags(tags...)))
 	}
 
-	return inputSrc, nil
+	return stereoscope.GetImageFromSource(ctx, destLocation, destSrc, opts...)
+}
+
+// imageSystemContext returns the context of containers/image reading the images, a multi-arch image is read
+// as the linux/amd64 one like the image load does.
+func imageSystemContext() *types.SystemContext {
+	return &types.SystemContext{
+		ArchitectureChoice:          "amd64",
+		OSChoice:                    "linux",
+		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+	}
 }
 
 func getImageLoadOptions() []stereoscope.Option {
@@ -1,6 +1,7 @@
 package scan
 
 import (
+	"context"
 	"github.com/smartystreets/goconvey/convey"
 	"testing"
 )
@@ -10,25 +11,25 @@ func TestLoadImageBadInput(t *testing.T) {
 		registryHandler := NewRegistryHandler()
 
 		convey.Convey("with unknown scheme", func() {
-			img, err := registryHandler.LoadImage("foo://latest", Option{})
+			img, err := registryHandler.LoadImage(context.Background(), "foo://latest", Option{})
 			convey.So(img, convey.ShouldBeNil)
 			convey.So(err, convey.ShouldNotBeNil)
 		})
 
 		convey.Convey("with local path to a non-existent file", func() {
-			img, err := registryHandler.LoadImage("/foo/bar/baz", Option{})
+			img, err := registryHandler.LoadImage(context.Background(), "/foo/bar/baz", Option{})
 			convey.So(img, convey.ShouldBeNil)
 			convey.So(err, convey.ShouldNotBeNil)
 		})
 
 		convey.Convey("unable to expand home directory", func() {
-			img, err := registryHandler.LoadImage("~foo", Option{})
+			img, err := registryHandler.LoadImage(context.Background(), "~foo", Option{})
 			convey.So(img, convey.ShouldBeNil)
 			convey.So(err, convey.ShouldNotBeNil)
 		})
 
 		convey.Convey("unknown source", func() {
-			img, err := registryHandler.LoadImage("foo:bar:baz", Option{})
+			img, err := registryHandler.LoadImage(context.Background(), "foo:bar:baz", Option{})
 			convey.So(img, convey.ShouldBeNil)
 			convey.So(err, convey.ShouldNotBeNil)
 		})
@@ -0,0 +1,187 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anchore/syft/syft/formats"
+	"github.com/anchore/syft/syft/source"
+	godigest "github.com/opencontainers/go-digest"
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+)
+
+// SBOMPrefix marks an input as an existing sbom file instead of an image, e.g. sbom:build/sbom.cdx.json.
+const SBOMPrefix = "sbom:"
+
+// importedConfiguration is the configuration recorded in the descriptor of an imported sbom.
+type importedConfiguration struct {
+	// Format is the format of the sbom file, e.g. cyclonedx-json
+	Format string `json:"format"`
+	// File is the path of the sbom file
+	File string `json:"file"`
+}
+
+// IsSBOMInput reports if the input is an existing sbom file, i.e. prefixed with sbom:.
+func IsSBOMInput(input string) bool {
+	return strings.HasPrefix(input, SBOMPrefix)
+}
+
+// SBOMPath will return the path of the sbom file input.
+func SBOMPath(input string) string {
+	return strings.TrimPrefix(input, SBOMPrefix)
+}
+
+// GenerateSBOMFromFile decodes the sbom file, in syft json, SPDX 2.x json or tag-value, or CycloneDX json or xml,
+// and returns it for the image described by its metadata. The full tag and the manifest digest override the ones
+// of the metadata, without any the digest is the sha256 of the packages and the image is named after the file.
+func GenerateSBOMFromFile(path, forceFullTag, forceDigest string) (*Bom, error) {
+	if forceDigest != "" {
+		if _, err := godigest.Parse(forceDigest); err != nil {
+			errMsg := fmt.Sprintf("Invalid digest %q, must be sha256:<hex>", forceDigest)
+			return nil, cberr.NewError(cberr.ConfigErr, errMsg, err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to read sbom file %v", path)
+
+		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	defer func() { _ = file.Close() }()
+
+	decoded, format, err := formats.Decode(file)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to decode sbom file %v, must be syft json, SPDX json or CycloneDX json or xml",
+			path)
+
+		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	// only the image metadata is kept, the sbom of a directory or a file is analyzed as an image
+	metadata := source.ImageMetadata{UserInput: path}
+	if decoded.Source.Scheme == source.ImageScheme || decoded.Source.ImageMetadata.UserInput != "" {
+		metadata = decoded.Source.ImageMetadata
+	}
+
+	doc, err := bom.NewJSONDocument(decoded.Artifacts.PackageCatalog,
+		source.Metadata{Scheme: source.ImageScheme, ImageMetadata: metadata},
+		decoded.Artifacts.LinuxDistribution, source.SquashedScope)
+	if err != nil {
+		errMsg := fmt.Sprintf("Failed to parse the sbom for %v", path)
+		e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+		logrus.Errorln(e.Error())
+
+		return nil, e
+	}
+
+	manifestDigest := forceDigest
+	if manifestDigest == "" {
+		manifestDigest = validDigest(metadata.ManifestDigest)
+	}
+
+	if manifestDigest == "" {
+		if manifestDigest, err = directoryDigest(doc); err != nil {
+			errMsg := fmt.Sprintf("Failed to compute the digest of %v", path)
+			e := cberr.NewError(cberr.SBOMGenerationErr, errMsg, err)
+			logrus.Errorln(e.Error())
+
+			return nil, e
+		}
+	}
+
+	target, ok := doc.Source.Target.(bom.JSONImageSource)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert taget to image metadata type")
+	}
+
+	target.ManifestDigest = manifestDigest
+
+	// the id of the image is rarely kept by the other tools, the manifest digest identifies the image instead
+	target.ID = validDigest(metadata.ID)
+	if target.ID == "" {
+		target.ID = manifestDigest
+	}
+
+	var fullTag string
+
+	target.Tags = formatTags(target.Tags, forceFullTag)
+	if len(target.Tags) > 0 {
+		fullTag = revertAnchoreDigestChange(target.Tags[len(target.Tags)-1])
+	} else {
+		fullTag = importedFullTag(path, metadata.UserInput, manifestDigest)
+		target.Tags = []string{fullTag}
+	}
+
+	doc.Source.Target = target
+	doc.Descriptor = &bom.JSONDescriptor{
+		Name:    decoded.Descriptor.Name,
+		Version: decoded.Descriptor.Version,
+		Configuration: importedConfiguration{
+			Format: format.ID().String(),
+			File:   path,
+		},
+	}
+
+	logrus.WithFields(logrus.Fields{"fullTag": fullTag, "format": format.ID()}).Infof("SBOM imported successfully")
+
+	return &Bom{
+		FullTag:        fullTag,
+		ManifestDigest: manifestDigest,
+		Packages:       doc,
+	}, nil
+}
+
+// ExtractDataFromSBOMFile will decode the sbom file input, the errors are published to the bus.
+func (s *Scanner) ExtractDataFromSBOMFile(input string, opts Option) (*Bom, bool) {
+	generatedBom, err := GenerateSBOMFromFile(SBOMPath(input), opts.FullTag, opts.ManifestDigest)
+	if err != nil {
+		bus.Publish(bus.NewErrorEvent(err))
+		return nil, true
+	}
+
+	metrictool.Add(metrictool.CounterPackages, int64(len(generatedBom.Packages.Artifacts)))
+
+	return generatedBom, false
+}
+
+// importedFullTag will return the tag of the image described by the sbom, or a tag named after the file.
+func importedFullTag(path, userInput, manifestDigest string) string {
+	if userInput != "" && userInput != path {
+		if tag, err := formatTag(userInput); err == nil {
+			return tag
+		}
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	tag := fmt.Sprintf("%s:%s", directoryName(name), strings.TrimPrefix(manifestDigest, "sha256:"))
+
+	formatted, err := addDefaultValuesToFullTag(tag)
+	if err != nil {
+		logrus.WithFields(logrus.Fields{"err": err, "tag": tag}).Warning("fail formatting the tag of the sbom file")
+	}
+
+	return formatted
+}
+
+// validDigest will return the digest if it is a valid one, or empty.
+func validDigest(value string) string {
+	if _, err := godigest.Parse(value); err != nil {
+		return ""
+	}
+
+	return value
+}
@@ -0,0 +1,98 @@
+package scan
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/anchore/syft/syft/formats"
+	"github.com/anchore/syft/syft/formats/cyclonedxjson"
+	"github.com/anchore/syft/syft/formats/cyclonedxxml"
+	"github.com/anchore/syft/syft/formats/spdxjson"
+	"github.com/anchore/syft/syft/formats/syftjson"
+	"github.com/anchore/syft/syft/pkg"
+	"github.com/anchore/syft/syft/sbom"
+	"github.com/anchore/syft/syft/source"
+	"github.com/stretchr/testify/require"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
+)
+
+const (
+	testManifestDigest = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	testImageID        = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func testSBOM() sbom.SBOM {
+	catalog := pkg.NewCatalog(
+		pkg.Package{Name: "openssl", Version: "1.1.1k-r0", Type: pkg.ApkPkg, PURL: "pkg:apk/alpine/openssl@1.1.1k-r0"},
+		pkg.Package{Name: "requests", Version: "2.19.0", Type: pkg.PythonPkg, PURL: "pkg:pypi/requests@2.19.0"},
+	)
+
+	return sbom.SBOM{
+		Artifacts: sbom.Artifacts{PackageCatalog: catalog},
+		Source: source.Metadata{
+			Scheme: source.ImageScheme,
+			ImageMetadata: source.ImageMetadata{
+				UserInput:      "example.com/app:1.0",
+				ID:             testImageID,
+				ManifestDigest: testManifestDigest,
+				Tags:           []string{"example.com/app:1.0"},
+			},
+		},
+		Descriptor: sbom.Descriptor{Name: "syft", Version: "0.74.0"},
+	}
+}
+
+func writeSBOM(t *testing.T, format sbom.Format, file string) string {
+	data, err := formats.Encode(testSBOM(), format)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), file)
+	require.NoError(t, ioutil.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func TestGenerateSBOMFromFile(t *testing.T) {
+	for _, format := range []sbom.Format{
+		syftjson.Format(), cyclonedxjson.Format(), cyclonedxxml.Format(), spdxjson.Format2_3(),
+	} {
+		path := writeSBOM(t, format, "sbom.out")
+
+		generated, err := GenerateSBOMFromFile(path, "", "")
+		require.NoError(t, err, format.ID())
+		require.Len(t, generated.Packages.Artifacts, 2, format.ID())
+		require.Equal(t, "image", generated.Packages.Source.Type)
+		require.Equal(t, format.ID().String(),
+			generated.Packages.Descriptor.Configuration.(importedConfiguration).Format)
+	}
+
+	// the syft json keeps the identity of the image
+	generated, err := GenerateSBOMFromFile(writeSBOM(t, syftjson.Format(), "sbom.json"), "", "")
+	require.NoError(t, err)
+	require.Equal(t, "example.com/app:1.0", generated.FullTag)
+	require.Equal(t, testManifestDigest, generated.ManifestDigest)
+	require.Equal(t, testImageID, generated.Packages.Source.Target.(bom.JSONImageSource).ID)
+
+	// the flags override it
+	generated, err = GenerateSBOMFromFile(writeSBOM(t, syftjson.Format(), "sbom.json"),
+		"example.com/app:2.0", testImageID)
+	require.NoError(t, err)
+	require.Equal(t, "example.com/app:2.0", generated.FullTag)
+	require.Equal(t, testImageID, generated.ManifestDigest)
+
+	// the SPDX documents have no identity, the image is named after the file
+	generated, err = GenerateSBOMFromFile(writeSBOM(t, spdxjson.Format2_3(), "App.spdx.json"), "", "")
+	require.NoError(t, err)
+	require.Regexp(t, `^docker.io/library/app.spdx:[0-9a-f]{64}$`, generated.FullTag)
+	require.Equal(t, "sha256:"+generated.FullTag[len(generated.FullTag)-64:], generated.ManifestDigest)
+
+	_, err = GenerateSBOMFromFile(writeSBOM(t, syftjson.Format(), "sbom.json"), "", "md5:abc")
+	require.Error(t, err)
+
+	notSBOM := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, ioutil.WriteFile(notSBOM, []byte("not an sbom"), 0o600))
+
+	_, err = GenerateSBOMFromFile(notSBOM, "", "")
+	require.Error(t, err)
+}
@@ -1,21 +1,20 @@
 package scan
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
 	"net/http"
 	"net/url"
-	"os"
-	"os/signal"
 	"strings"
-	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/bus"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/metrictool"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/version"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
@@ -29,9 +28,10 @@ const (
 	imagesRoute     = service + "/images/%s"
 	getVulnTemplate = imagesRoute + "/vulnerabilities"
 
-	operationRoute    = imagesRoute + "/operations/%s"
-	putSBOMTemplate   = operationRoute
-	getStatusTemplate = operationRoute + "/status"
+	operationRoute          = imagesRoute + "/operations/%s"
+	putSBOMTemplate         = operationRoute
+	getStatusTemplate       = operationRoute + "/status"
+	cancelOperationTemplate = operationRoute + "/cancel"
 
 	imageIDRoute        = service + "/image_id/%s"
 	getVulnByIDTemplate = imageIDRoute + "/vulnerabilities"
@@ -66,6 +66,9 @@ type Handler struct {
 	pollDuration time.Duration
 }
 
+// cancelTimeout is the time given to notify the backend, since the context of the scan is already done.
+const cancelTimeout = 5 * time.Second
+
 // NewScanHandler will create a handler for scan cmd.
 func NewScanHandler(saasTmpl, orgKey, apiID, apiKey string, bom *Bom, layers []layers.Layer) *Handler {
 	saasTmpl = strings.Trim(saasTmpl, "/")
@@ -105,13 +108,18 @@ func (h *Handler) AttachData(bom *Bom, layers []layers.Layer, buildStep, namespa
 // HealthCheck will check the health of the service backend.
 func (h Handler) HealthCheck() error {
 	healthCheckPath := fmt.Sprintf(healthCheckTemplate, h.basePath)
-	_, _, err := h.session.RequestData(http.MethodGet, healthCheckPath, nil)
+	if _, _, err := h.session.RequestData(http.MethodGet, healthCheckPath, nil); err != nil {
+		msg := `The image scanning service is not healthy, run "cbctl doctor" to diagnose the environment`
+		return cberr.NewError(cberr.ErrorCode(err), msg, err)
+	}
 
-	return err
+	return nil
 }
 
 // Scan will send payload to image scanning service and fetch the result back.
-func (h *Handler) Scan(operationID string, opts Option) (*image.ScannedImage, error) {
+//
+// Once ctx is done, e.g. on ^C, the in-flight requests are aborted and the backend is notified to cancel the operation.
+func (h *Handler) Scan(ctx context.Context, operationID string, opts Option) (*image.ScannedImage, error) {
 	// update scan duration from the options
 	if opts.Timeout > 0 {
 		h.pollDuration = time.Duration(opts.Timeout) * time.Second
@@ -135,24 +143,20 @@ func (h *Handler) Scan(operationID string, opts Option) (*image.ScannedImage, er
 	logrus.Infof("Scanning image, current stage: %v", currentStage)
 	stage.Current = currentStage
 
-	signalChan := make(chan os.Signal, 1)
 	errChan := make(chan error, 1)
-	scannedImageChan := make(chan *image.ScannedImage)
-
-	// catch incoming signal, if it is a ^C, send a cancel notification to backend
-	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	scannedImageChan := make(chan *image.ScannedImage, 1)
 
 	go func() {
 		currentStage = "uploading software bills of material"
 		logrus.Infof("Scanning image, current stage: %v", currentStage)
 		stage.Current = currentStage
 
-		if status, err := h.PutBomAndLayersToAnalysisAPI(operationID, opts); err != nil {
+		if status, err := h.PutBomAndLayersToAnalysisAPI(ctx, operationID, opts); err != nil {
 			errChan <- err
 			return
 		} else if status == FinishedStatus {
 			// the result should be fetched directly from backend
-			scannedImage, e := h.GetImageVulnerability(h.bom.ManifestDigest, "", "")
+			scannedImage, e := h.getImageVulnerability(ctx, h.bom.ManifestDigest, "", "")
 			if e == nil && scannedImage != nil && scannedImage.ScanStatus == "SCANNED" {
 				currentStage = "fetching result"
 				logrus.Infof("Scanning image, current stage: %v", currentStage)
@@ -168,9 +172,13 @@ func (h *Handler) Scan(operationID string, opts Option) (*image.ScannedImage, er
 		stage.Current = currentStage
 
 		// sleep for a while, since we need some time to analyze
-		time.Sleep(h.pollPause)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(h.pollPause):
+		}
 
-		scannedImage, err := h.GetResponseFromScanAPI(h.bom.ManifestDigest, operationID)
+		scannedImage, err := h.GetResponseFromScanAPI(ctx, h.bom.ManifestDigest, operationID)
 		if err != nil {
 			errChan <- err
 			return
@@ -185,12 +193,18 @@ func (h *Handler) Scan(operationID string, opts Option) (*image.ScannedImage, er
 
 	for {
 		select {
+		case <-ctx.Done():
+			logrus.Errorln("detect ^C signal interruption")
+			h.CancelOperation(h.bom.ManifestDigest, operationID)
+
+			return nil, cberr.NewError(cberr.InterruptedErr, "Scan interrupted", ctx.Err())
 		case err := <-errChan:
+			// the worker may fail on the interruption before it is seen here
+			if ctx.Err() != nil {
+				h.CancelOperation(h.bom.ManifestDigest, operationID)
+			}
+
 			return nil, err
-		case <-signalChan:
-			err := fmt.Errorf("detect ^C signal interruption")
-			logrus.Errorln(err)
-			errChan <- err
 		case scannedImage := <-scannedImageChan:
 			if scannedImage != nil {
 				return scannedImage, nil
@@ -199,17 +213,34 @@ func (h *Handler) Scan(operationID string, opts Option) (*image.ScannedImage, er
 	}
 }
 
+// CancelOperation will notify the backend to cancel the analysis operation, a failure is only logged.
+func (h Handler) CancelOperation(digest, operationID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), cancelTimeout)
+	defer cancel()
+
+	cancelPath := fmt.Sprintf(cancelOperationTemplate, h.basePath, digest, operationID)
+	if _, _, err := h.session.RequestDataWithContext(ctx, http.MethodPost, cancelPath, nil); err != nil {
+		logrus.WithError(err).Warnf("Failed to cancel operation %s", operationID)
+		return
+	}
+
+	logrus.Infof("Operation %s cancelled", operationID)
+}
+
 // PutBomAndLayersToAnalysisAPI will call the PUT API and upload sbom to image scanning service.
-func (h Handler) PutBomAndLayersToAnalysisAPI(operationID string, opts Option) (Status, error) {
+func (h Handler) PutBomAndLayersToAnalysisAPI(ctx context.Context, operationID string, opts Option) (Status, error) {
 	versionInfo := version.GetCurrentVersion()
 
 	payload := NewAnalysisPayload(&h.bom.Packages, h.layers, h.buildStep, h.namespace, opts.ForceScan, versionInfo.SyftVersion, versionInfo.Version)
 
 	analysisPath := fmt.Sprintf(putSBOMTemplate, h.basePath, h.bom.ManifestDigest, operationID)
 
-	target, ok := h.bom.Packages.Source.Target.(bom.JSONImageSource)
+	// a directory has no image id, the one attached is the digest of its packages
+	if target, ok := h.bom.Packages.Source.Target.(bom.JSONImageSource); ok {
+		h.imageID = target.ID
+	}
 
-	if !ok {
+	if h.imageID == "" {
 		errMsg := "Failed to get imageID"
 		e := cberr.NewError(cberr.ScanFailedErr, errMsg, nil)
 		logrus.Error(e.Error())
@@ -217,8 +248,6 @@ func (h Handler) PutBomAndLayersToAnalysisAPI(operationID string, opts Option) (
 		return "", e
 	}
 
-	h.imageID = target.ID
-
 	if h.bom != nil && h.bom.FullTag != "" {
 		statusURLWithQueries, _ := url.Parse(analysisPath)
 		params := url.Values{}
@@ -228,7 +257,27 @@ func (h Handler) PutBomAndLayersToAnalysisAPI(operationID string, opts Option) (
 		analysisPath = statusURLWithQueries.String()
 	}
 
-	_, resp, err := h.session.RequestData(http.MethodPut, analysisPath, payload)
+	// the payload holds the sbom and the files of all the layers, stream it compressed with the progress on the bus
+	stage := &uploadStage{}
+	value := progress.StagedProgressable(&struct {
+		progress.Stager
+		progress.Progressable
+	}{
+		Stager:       stage,
+		Progressable: &stage.Manual,
+	})
+	bus.Publish(bus.NewEvent(bus.UploadStarted, value, false))
+
+	defer stage.SetCompleted()
+
+	span := metrictool.StartSpan(metrictool.StageUpload)
+	defer span.End()
+
+	streamOpts := httptool.StreamOptions{Compress: true, Progress: &stage.Manual}
+	_, resp, err := h.session.StreamDataWithContext(ctx, http.MethodPut, analysisPath, payload, streamOpts)
+	// the progress counts the compressed bytes written to the request, not the size of the json
+	metrictool.Add(metrictool.CounterBytesUploaded, stage.Current())
+	span.SetError(err)
 	if err != nil && cberr.ErrorCode(err) == cberr.HTTPUnsuccessfulResponseErr {
 		errMsg := "Failed to put sbom to the backend"
 		e := cberr.NewError(cberr.ScanFailedErr, errMsg, err)
@@ -242,34 +291,74 @@ func (h Handler) PutBomAndLayersToAnalysisAPI(operationID string, opts Option) (
 
 // GetResponseFromScanAPI will call the status API from image scanning service periodically,
 // once the status is "FINISHED", it will fetch the real result from vuln API.
-func (h Handler) GetResponseFromScanAPI(digest, operationID string) (*image.ScannedImage, error) {
+func (h Handler) GetResponseFromScanAPI(ctx context.Context, digest, operationID string) (*image.ScannedImage, error) {
+	// the status requests in flight are stopped on return
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	ticker := time.NewTicker(h.pollInterval)
 	defer ticker.Stop()
 
-	timeout := make(chan bool)
+	timeout := time.NewTimer(h.pollDuration)
+	defer timeout.Stop()
+
+	// the queue stage lasts until the backend picks up the analysis, the poll stage until the result is fetched
+	pollSpan := metrictool.StartSpan(metrictool.StagePoll)
+	queueSpan := metrictool.StartSpan(metrictool.StageQueue)
+
+	defer pollSpan.End()
+	defer queueSpan.End()
+
 	statusResult := make(chan StatusResponse)
+	statusErr := make(chan error)
 
-	go func() {
-		time.Sleep(h.pollDuration)
-		timeout <- true
-	}()
+	// the status request retries on transient failures, so skip the ticks while one is in flight
+	polling := false
 
 	for {
 		select {
-		case <-timeout:
+		case <-ctx.Done():
+			return nil, cberr.NewError(cberr.InterruptedErr, "Status polling interrupted", ctx.Err())
+		case <-timeout.C:
 			return nil, cberr.NewError(cberr.TimeoutErr, "Time out during status polling", nil)
 		case <-ticker.C:
+			if polling {
+				continue
+			}
+
+			polling = true
+
+			metrictool.Add(metrictool.CounterPollRequests, 1)
+
 			go func() {
-				if status, err := h.GetImageAnalysisStatus(digest, operationID); err == nil {
-					statusResult <- status
-				} else {
-					statusResult <- StatusResponse{OperationStatus: FailedStatus}
+				status, err := h.GetImageAnalysisStatus(ctx, digest, operationID)
+				if err != nil {
+					select {
+					case <-ctx.Done():
+					case statusErr <- err:
+					}
+
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+				case statusResult <- status:
 				}
 			}()
+		case err := <-statusErr:
+			errMsg := fmt.Sprintf("Failed to poll scan status of image [%s]", digest)
+			return nil, cberr.NewError(cberr.ScanFailedErr, errMsg, err)
 		case result := <-statusResult:
+			polling = false
+
+			if result.OperationStatus != QueuedStatus && result.OperationStatus != UploadedStatus {
+				queueSpan.End()
+			}
+
 			switch result.OperationStatus {
 			case FinishedStatus:
-				return h.GetImageVulnerability(digest, "", "")
+				return h.getImageVulnerability(ctx, digest, "", "")
 			case FailedStatus:
 				errMsg := fmt.Sprintf("Failed to scan image [%s]", digest)
 				return nil, cberr.NewError(cberr.TimeoutErr, errMsg, nil)
@@ -283,7 +372,7 @@ func (h Handler) GetResponseFromScanAPI(digest, operationID string) (*image.Scan
 }
 
 // GetImageAnalysisStatus will fetch the current analysis result of an image.
-func (h Handler) GetImageAnalysisStatus(digest, operationID string) (StatusResponse, error) {
+func (h Handler) GetImageAnalysisStatus(ctx context.Context, digest, operationID string) (StatusResponse, error) {
 	statusPath := fmt.Sprintf(getStatusTemplate, h.basePath, digest, operationID)
 	if h.bom != nil && h.bom.FullTag != "" {
 		statusURLWithQueries, _ := url.Parse(statusPath)
@@ -294,7 +383,7 @@ func (h Handler) GetImageAnalysisStatus(digest, operationID string) (StatusRespo
 	}
 
 	var response StatusResponse
-	statusCode, resp, err := h.session.RequestData(http.MethodGet, statusPath, nil)
+	statusCode, resp, err := h.session.RequestDataWithContext(ctx, http.MethodGet, statusPath, nil)
 	if err != nil {
 		if cberr.ErrorCode(err) == cberr.HTTPUnsuccessfulResponseErr {
 			errMsg := fmt.Sprintf("Failed to fetch status for image (%v)", digest)
@@ -326,6 +415,12 @@ func (h Handler) GetImageAnalysisStatus(digest, operationID string) (StatusRespo
 
 // GetImageVulnerability will fetch the vulnerability result via image digest.
 func (h Handler) GetImageVulnerability(digest, imageID, cliVersion string) (*image.ScannedImage, error) {
+	return h.getImageVulnerability(context.Background(), digest, imageID, cliVersion)
+}
+
+func (h Handler) getImageVulnerability(
+	ctx context.Context, digest, imageID, cliVersion string,
+) (*image.ScannedImage, error) {
 	var vulnPath string
 
 	if imageID != "" {
@@ -356,7 +451,7 @@ func (h Handler) GetImageVulnerability(digest, imageID, cliVersion string) (*ima
 		vulnPath = vulnURLWithQueries.String()
 	}
 
-	_, resp, err := h.session.RequestData(http.MethodGet, vulnPath, nil)
+	_, resp, err := h.session.RequestDataWithContext(ctx, http.MethodGet, vulnPath, nil)
 	if err != nil {
 		var errMsg string
 		if imageID != "" {
@@ -404,7 +499,9 @@ func (h Handler) GetImageVulnerability(digest, imageID, cliVersion string) (*ima
 }
 
 // GetImagesScanResultsFromBackendByImageID return scan image data if existed.
-func (h Handler) GetImagesScanResultsFromBackendByImageID(imageId, cliVersion string) (*image.ScannedImage, error) {
-	scannedImage, e := h.GetImageVulnerability("", imageId, cliVersion)
+func (h Handler) GetImagesScanResultsFromBackendByImageID(
+	ctx context.Context, imageId, cliVersion string,
+) (*image.ScannedImage, error) {
+	scannedImage, e := h.getImageVulnerability(ctx, "", imageId, cliVersion)
 	return scannedImage, e
 }
@@ -1,6 +1,7 @@
 package scan
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,7 +9,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/anchore/syft/syft/source"
 	"github.com/vmware/carbon-black-cloud-container-cli/internal/util/httptool"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/cberr"
+	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/image"
 )
 
@@ -50,7 +54,7 @@ func TestGetResponsePassNoForce(t *testing.T) {
 		pollInterval: 2 * time.Millisecond,
 	}
 
-	resp, err := mockScanHandler.Scan(mockOperationID, Option{ForceScan: false})
+	resp, err := mockScanHandler.Scan(context.Background(), mockOperationID, Option{ForceScan: false})
 	if err != nil {
 		t.Error(err)
 	}
@@ -77,7 +81,7 @@ func TestGetResponsePassWithForce(t *testing.T) {
 		t.Error(err)
 	}
 
-	resp, err := mockScanHandler.Scan(mockOperationID, Option{ForceScan: true})
+	resp, err := mockScanHandler.Scan(context.Background(), mockOperationID, Option{ForceScan: true})
 	if err != nil {
 		t.Error(err)
 	}
@@ -99,7 +103,7 @@ func TestGetResponse_Fail(t *testing.T) {
 		pollInterval: 2 * time.Millisecond,
 	}
 
-	resp, err := mockScanHandler.GetResponseFromScanAPI(mockDigest, mockOperationID)
+	resp, err := mockScanHandler.GetResponseFromScanAPI(context.Background(), mockDigest, mockOperationID)
 	if err == nil {
 		t.Error("expect an error but got nil")
 	}
@@ -163,3 +167,83 @@ func failedServerMock() *httptest.Server {
 
 	return httptest.NewServer(handler)
 }
+
+func TestScanInterrupted(t *testing.T) {
+	cancelled := make(chan bool, 1)
+	release := make(chan bool)
+
+	handler := http.NewServeMux()
+	handler.HandleFunc(fmt.Sprintf(putSBOMTemplate, "", mockDigest, mockOperationID), func(w http.ResponseWriter, r *http.Request) {
+		// hold the upload until the scan is interrupted
+		<-release
+	})
+	handler.HandleFunc(fmt.Sprintf(cancelOperationTemplate, "", mockDigest, mockOperationID), func(w http.ResponseWriter, r *http.Request) {
+		cancelled <- true
+		w.WriteHeader(204)
+	})
+
+	mockServer := httptest.NewServer(handler)
+	defer mockServer.Close()
+	defer close(release)
+
+	mockBom := &Bom{ManifestDigest: mockDigest}
+	mockBom.Packages.Source.Target = bom.JSONImageSource{ImageMetadata: source.ImageMetadata{ID: mockDigest}}
+
+	mockScanHandler := &Handler{
+		bom:          mockBom,
+		session:      httptool.NewRequestSession("", ""),
+		basePath:     mockServer.URL,
+		pollDuration: 5 * time.Second,
+		pollInterval: 2 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	resp, err := mockScanHandler.Scan(ctx, mockOperationID, Option{})
+	if cberr.ErrorCode(err) != cberr.InterruptedErr {
+		t.Errorf("expect an interrupted error, got: %v", err)
+	}
+
+	if resp != nil {
+		t.Errorf("response should be a nil struct, got %v", resp)
+	}
+
+	select {
+	case <-cancelled:
+	default:
+		t.Error("expect the operation to be cancelled in the backend")
+	}
+}
+
+func TestGetResponseTimeoutStopsPolling(t *testing.T) {
+	aborted := make(chan bool, 1)
+
+	handler := http.NewServeMux()
+	handler.HandleFunc(fmt.Sprintf(getStatusTemplate, "", mockDigest, mockOperationID), func(w http.ResponseWriter, r *http.Request) {
+		// hold the status until the polling gives up on it
+		<-r.Context().Done()
+		aborted <- true
+	})
+
+	mockServer := httptest.NewServer(handler)
+	defer mockServer.Close()
+
+	mockScanHandler := &Handler{
+		session:      httptool.NewRequestSession("", ""),
+		basePath:     mockServer.URL,
+		pollDuration: 50 * time.Millisecond,
+		pollInterval: 2 * time.Millisecond,
+	}
+
+	_, err := mockScanHandler.GetResponseFromScanAPI(context.Background(), mockDigest, mockOperationID)
+	if cberr.ErrorCode(err) != cberr.TimeoutErr {
+		t.Errorf("expect a timeout error, got: %v", err)
+	}
+
+	select {
+	case <-aborted:
+	case <-time.After(5 * time.Second):
+		t.Error("expect the status request in flight to be aborted")
+	}
+}
@@ -1,9 +1,13 @@
 package scan
 
 import (
+	"fmt"
+	"strconv"
+
+	"github.com/dustin/go-humanize"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/bom"
 	"github.com/vmware/carbon-black-cloud-container-cli/pkg/model/layers"
-	"strconv"
+	progress "github.com/wagoodman/go-progress"
 )
 
 const (
@@ -75,3 +79,13 @@ func (payload AnalysisPayload) Rows() [][]string {
 
 	return result
 }
+
+// uploadStage is the progress of the payload upload, its stage shows the bytes sent out of the total.
+type uploadStage struct {
+	progress.Manual
+}
+
+// Stage returns the bytes of the payload sent, its total is unknown until it is encoded.
+func (u *uploadStage) Stage() string {
+	return fmt.Sprintf("%s sent", humanize.Bytes(uint64(u.Current())))
+}